@@ -69,11 +69,14 @@ func (s *AddressesService) Update(ctx context.Context, addressID string, address
 	return &result, err
 }
 
-// List lists all addresses
+// List lists all addresses, following pagination until every page is read
 func (s *AddressesService) List(ctx context.Context) ([]Address, error) {
-	var addresses []Address
-	err := s.client.get(ctx, "/api/addresses", &addresses)
-	return addresses, err
+	return s.ListPager(nil).All(ctx)
+}
+
+// ListPager returns a Pager over the address book
+func (s *AddressesService) ListPager(opts *ListOptions) *Pager[Address] {
+	return newListPager[Address](s.client, "/api/addresses", opts)
 }
 
 // Delete deletes an address
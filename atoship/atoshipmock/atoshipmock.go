@@ -0,0 +1,484 @@
+// Package atoshipmock provides stub implementations of the atoship service
+// interfaces for unit-testing code that depends on the SDK.
+//
+// Each stub has one Func field per method. Calling a method whose Func is
+// nil returns an error wrapping ErrNotStubbed.
+//
+//	stubs := &atoshipmock.API{}
+//	stubs.Tracking.TrackFunc = func(ctx context.Context, n string) (*atoship.TrackingInfo, error) {
+//		return &atoship.TrackingInfo{TrackingNumber: n, Delivered: true}, nil
+//	}
+//	svc := NewNotifier(stubs.API())
+package atoshipmock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+// ErrNotStubbed is returned by stub methods whose Func field is nil
+var ErrNotStubbed = errors.New("atoshipmock: method not stubbed")
+
+func notStubbed(method string) error {
+	return fmt.Errorf("%w: %s", ErrNotStubbed, method)
+}
+
+// API bundles a stub for every service
+type API struct {
+	Orders    Orders
+	Addresses Addresses
+	Shipping  Shipping
+	Tracking  Tracking
+	Users     Users
+	Admin     Admin
+	Carriers  Carriers
+	Webhooks  Webhooks
+}
+
+// API returns the stubs as an atoship.API
+func (m *API) API() *atoship.API {
+	return &atoship.API{
+		Orders:    &m.Orders,
+		Addresses: &m.Addresses,
+		Shipping:  &m.Shipping,
+		Tracking:  &m.Tracking,
+		Users:     &m.Users,
+		Admin:     &m.Admin,
+		Carriers:  &m.Carriers,
+		Webhooks:  &m.Webhooks,
+	}
+}
+
+// Orders is a stub atoship.OrdersAPI
+type Orders struct {
+	CreateFunc     func(ctx context.Context, req *atoship.CreateOrderRequest) (*atoship.Order, error)
+	GetFunc        func(ctx context.Context, orderID string) (*atoship.Order, error)
+	UpdateFunc     func(ctx context.Context, orderID string, req *atoship.UpdateOrderRequest) (*atoship.Order, error)
+	ListFunc       func(ctx context.Context, opts *atoship.ListOrdersOptions) (*atoship.OrderListResponse, error)
+	ListPagerFunc  func(opts *atoship.ListOrdersOptions) *atoship.Pager[atoship.Order]
+	ListAllFunc    func(ctx context.Context, opts *atoship.ListOrdersOptions, fn func(*atoship.Order) error) error
+	DeleteFunc     func(ctx context.Context, orderID string) error
+	ShipFunc       func(ctx context.Context, orderID string, trackingNumber string, carrier string) (*atoship.Order, error)
+	CancelFunc     func(ctx context.Context, orderID string, reason string) (*atoship.Order, error)
+	BulkCreateFunc func(ctx context.Context, orders []*atoship.CreateOrderRequest) (*atoship.BulkCreateResponse, error)
+}
+
+// Create calls CreateFunc
+func (m *Orders) Create(ctx context.Context, req *atoship.CreateOrderRequest) (*atoship.Order, error) {
+	if m.CreateFunc == nil {
+		return nil, notStubbed("Orders.Create")
+	}
+	return m.CreateFunc(ctx, req)
+}
+
+// Get calls GetFunc
+func (m *Orders) Get(ctx context.Context, orderID string) (*atoship.Order, error) {
+	if m.GetFunc == nil {
+		return nil, notStubbed("Orders.Get")
+	}
+	return m.GetFunc(ctx, orderID)
+}
+
+// Update calls UpdateFunc
+func (m *Orders) Update(ctx context.Context, orderID string, req *atoship.UpdateOrderRequest) (*atoship.Order, error) {
+	if m.UpdateFunc == nil {
+		return nil, notStubbed("Orders.Update")
+	}
+	return m.UpdateFunc(ctx, orderID, req)
+}
+
+// List calls ListFunc
+func (m *Orders) List(ctx context.Context, opts *atoship.ListOrdersOptions) (*atoship.OrderListResponse, error) {
+	if m.ListFunc == nil {
+		return nil, notStubbed("Orders.List")
+	}
+	return m.ListFunc(ctx, opts)
+}
+
+// ListPager calls ListPagerFunc
+func (m *Orders) ListPager(opts *atoship.ListOrdersOptions) *atoship.Pager[atoship.Order] {
+	if m.ListPagerFunc == nil {
+		return atoship.NewPager(func(context.Context, int, int) (*atoship.Page[atoship.Order], error) {
+			return nil, notStubbed("Orders.ListPager")
+		}, nil)
+	}
+	return m.ListPagerFunc(opts)
+}
+
+// ListAll calls ListAllFunc
+func (m *Orders) ListAll(ctx context.Context, opts *atoship.ListOrdersOptions, fn func(*atoship.Order) error) error {
+	if m.ListAllFunc == nil {
+		return notStubbed("Orders.ListAll")
+	}
+	return m.ListAllFunc(ctx, opts, fn)
+}
+
+// Delete calls DeleteFunc
+func (m *Orders) Delete(ctx context.Context, orderID string) error {
+	if m.DeleteFunc == nil {
+		return notStubbed("Orders.Delete")
+	}
+	return m.DeleteFunc(ctx, orderID)
+}
+
+// Ship calls ShipFunc
+func (m *Orders) Ship(ctx context.Context, orderID string, trackingNumber string, carrier string) (*atoship.Order, error) {
+	if m.ShipFunc == nil {
+		return nil, notStubbed("Orders.Ship")
+	}
+	return m.ShipFunc(ctx, orderID, trackingNumber, carrier)
+}
+
+// Cancel calls CancelFunc
+func (m *Orders) Cancel(ctx context.Context, orderID string, reason string) (*atoship.Order, error) {
+	if m.CancelFunc == nil {
+		return nil, notStubbed("Orders.Cancel")
+	}
+	return m.CancelFunc(ctx, orderID, reason)
+}
+
+// BulkCreate calls BulkCreateFunc
+func (m *Orders) BulkCreate(ctx context.Context, orders []*atoship.CreateOrderRequest) (*atoship.BulkCreateResponse, error) {
+	if m.BulkCreateFunc == nil {
+		return nil, notStubbed("Orders.BulkCreate")
+	}
+	return m.BulkCreateFunc(ctx, orders)
+}
+
+// Addresses is a stub atoship.AddressesAPI
+type Addresses struct {
+	CreateFunc    func(ctx context.Context, address *atoship.Address) (*atoship.Address, error)
+	GetFunc       func(ctx context.Context, addressID string) (*atoship.Address, error)
+	UpdateFunc    func(ctx context.Context, addressID string, address *atoship.Address) (*atoship.Address, error)
+	ListFunc      func(ctx context.Context) ([]atoship.Address, error)
+	ListPagerFunc func(opts *atoship.ListOptions) *atoship.Pager[atoship.Address]
+	DeleteFunc    func(ctx context.Context, addressID string) error
+	ValidateFunc  func(ctx context.Context, req *atoship.ValidateAddressRequest) (*atoship.ValidateAddressResponse, error)
+	SearchFunc    func(ctx context.Context, query string, country string) ([]atoship.Address, error)
+}
+
+// Create calls CreateFunc
+func (m *Addresses) Create(ctx context.Context, address *atoship.Address) (*atoship.Address, error) {
+	if m.CreateFunc == nil {
+		return nil, notStubbed("Addresses.Create")
+	}
+	return m.CreateFunc(ctx, address)
+}
+
+// Get calls GetFunc
+func (m *Addresses) Get(ctx context.Context, addressID string) (*atoship.Address, error) {
+	if m.GetFunc == nil {
+		return nil, notStubbed("Addresses.Get")
+	}
+	return m.GetFunc(ctx, addressID)
+}
+
+// Update calls UpdateFunc
+func (m *Addresses) Update(ctx context.Context, addressID string, address *atoship.Address) (*atoship.Address, error) {
+	if m.UpdateFunc == nil {
+		return nil, notStubbed("Addresses.Update")
+	}
+	return m.UpdateFunc(ctx, addressID, address)
+}
+
+// List calls ListFunc
+func (m *Addresses) List(ctx context.Context) ([]atoship.Address, error) {
+	if m.ListFunc == nil {
+		return nil, notStubbed("Addresses.List")
+	}
+	return m.ListFunc(ctx)
+}
+
+// ListPager calls ListPagerFunc
+func (m *Addresses) ListPager(opts *atoship.ListOptions) *atoship.Pager[atoship.Address] {
+	if m.ListPagerFunc == nil {
+		return atoship.NewPager(func(context.Context, int, int) (*atoship.Page[atoship.Address], error) {
+			return nil, notStubbed("Addresses.ListPager")
+		}, nil)
+	}
+	return m.ListPagerFunc(opts)
+}
+
+// Delete calls DeleteFunc
+func (m *Addresses) Delete(ctx context.Context, addressID string) error {
+	if m.DeleteFunc == nil {
+		return notStubbed("Addresses.Delete")
+	}
+	return m.DeleteFunc(ctx, addressID)
+}
+
+// Validate calls ValidateFunc
+func (m *Addresses) Validate(ctx context.Context, req *atoship.ValidateAddressRequest) (*atoship.ValidateAddressResponse, error) {
+	if m.ValidateFunc == nil {
+		return nil, notStubbed("Addresses.Validate")
+	}
+	return m.ValidateFunc(ctx, req)
+}
+
+// Search calls SearchFunc
+func (m *Addresses) Search(ctx context.Context, query string, country string) ([]atoship.Address, error) {
+	if m.SearchFunc == nil {
+		return nil, notStubbed("Addresses.Search")
+	}
+	return m.SearchFunc(ctx, query, country)
+}
+
+// Shipping is a stub atoship.ShippingAPI
+type Shipping struct {
+	GetRatesFunc      func(ctx context.Context, req *atoship.RateRequest) ([]atoship.ShippingRate, error)
+	PurchaseLabelFunc func(ctx context.Context, req *atoship.PurchaseLabelRequest) (*atoship.ShippingLabel, error)
+	GetLabelFunc      func(ctx context.Context, labelID string) (*atoship.ShippingLabel, error)
+	CancelLabelFunc   func(ctx context.Context, labelID string) (*atoship.ShippingLabel, error)
+}
+
+// GetRates calls GetRatesFunc
+func (m *Shipping) GetRates(ctx context.Context, req *atoship.RateRequest) ([]atoship.ShippingRate, error) {
+	if m.GetRatesFunc == nil {
+		return nil, notStubbed("Shipping.GetRates")
+	}
+	return m.GetRatesFunc(ctx, req)
+}
+
+// PurchaseLabel calls PurchaseLabelFunc
+func (m *Shipping) PurchaseLabel(ctx context.Context, req *atoship.PurchaseLabelRequest) (*atoship.ShippingLabel, error) {
+	if m.PurchaseLabelFunc == nil {
+		return nil, notStubbed("Shipping.PurchaseLabel")
+	}
+	return m.PurchaseLabelFunc(ctx, req)
+}
+
+// GetLabel calls GetLabelFunc
+func (m *Shipping) GetLabel(ctx context.Context, labelID string) (*atoship.ShippingLabel, error) {
+	if m.GetLabelFunc == nil {
+		return nil, notStubbed("Shipping.GetLabel")
+	}
+	return m.GetLabelFunc(ctx, labelID)
+}
+
+// CancelLabel calls CancelLabelFunc
+func (m *Shipping) CancelLabel(ctx context.Context, labelID string) (*atoship.ShippingLabel, error) {
+	if m.CancelLabelFunc == nil {
+		return nil, notStubbed("Shipping.CancelLabel")
+	}
+	return m.CancelLabelFunc(ctx, labelID)
+}
+
+// Tracking is a stub atoship.TrackingAPI
+type Tracking struct {
+	TrackFunc            func(ctx context.Context, trackingNumber string) (*atoship.TrackingInfo, error)
+	TrackWithCarrierFunc func(ctx context.Context, trackingNumber string, carrier string) (*atoship.TrackingInfo, error)
+	BatchTrackFunc       func(ctx context.Context, trackingNumbers []string) ([]atoship.TrackingInfo, error)
+}
+
+// Track calls TrackFunc
+func (m *Tracking) Track(ctx context.Context, trackingNumber string) (*atoship.TrackingInfo, error) {
+	if m.TrackFunc == nil {
+		return nil, notStubbed("Tracking.Track")
+	}
+	return m.TrackFunc(ctx, trackingNumber)
+}
+
+// TrackWithCarrier calls TrackWithCarrierFunc
+func (m *Tracking) TrackWithCarrier(ctx context.Context, trackingNumber string, carrier string) (*atoship.TrackingInfo, error) {
+	if m.TrackWithCarrierFunc == nil {
+		return nil, notStubbed("Tracking.TrackWithCarrier")
+	}
+	return m.TrackWithCarrierFunc(ctx, trackingNumber, carrier)
+}
+
+// BatchTrack calls BatchTrackFunc
+func (m *Tracking) BatchTrack(ctx context.Context, trackingNumbers []string) ([]atoship.TrackingInfo, error) {
+	if m.BatchTrackFunc == nil {
+		return nil, notStubbed("Tracking.BatchTrack")
+	}
+	return m.BatchTrackFunc(ctx, trackingNumbers)
+}
+
+// Users is a stub atoship.UsersAPI
+type Users struct {
+	GetProfileFunc    func(ctx context.Context) (*atoship.User, error)
+	UpdateProfileFunc func(ctx context.Context, updates *atoship.User) (*atoship.User, error)
+}
+
+// GetProfile calls GetProfileFunc
+func (m *Users) GetProfile(ctx context.Context) (*atoship.User, error) {
+	if m.GetProfileFunc == nil {
+		return nil, notStubbed("Users.GetProfile")
+	}
+	return m.GetProfileFunc(ctx)
+}
+
+// UpdateProfile calls UpdateProfileFunc
+func (m *Users) UpdateProfile(ctx context.Context, updates *atoship.User) (*atoship.User, error) {
+	if m.UpdateProfileFunc == nil {
+		return nil, notStubbed("Users.UpdateProfile")
+	}
+	return m.UpdateProfileFunc(ctx, updates)
+}
+
+// Admin is a stub atoship.AdminAPI
+type Admin struct {
+	GetStatsFunc func(ctx context.Context) (*atoship.Stats, error)
+}
+
+// GetStats calls GetStatsFunc
+func (m *Admin) GetStats(ctx context.Context) (*atoship.Stats, error) {
+	if m.GetStatsFunc == nil {
+		return nil, notStubbed("Admin.GetStats")
+	}
+	return m.GetStatsFunc(ctx)
+}
+
+// Carriers is a stub atoship.CarriersAPI
+type Carriers struct {
+	ListFunc      func(ctx context.Context) ([]atoship.Carrier, error)
+	ListPagerFunc func(opts *atoship.ListOptions) *atoship.Pager[atoship.Carrier]
+}
+
+// List calls ListFunc
+func (m *Carriers) List(ctx context.Context) ([]atoship.Carrier, error) {
+	if m.ListFunc == nil {
+		return nil, notStubbed("Carriers.List")
+	}
+	return m.ListFunc(ctx)
+}
+
+// ListPager calls ListPagerFunc
+func (m *Carriers) ListPager(opts *atoship.ListOptions) *atoship.Pager[atoship.Carrier] {
+	if m.ListPagerFunc == nil {
+		return atoship.NewPager(func(context.Context, int, int) (*atoship.Page[atoship.Carrier], error) {
+			return nil, notStubbed("Carriers.ListPager")
+		}, nil)
+	}
+	return m.ListPagerFunc(opts)
+}
+
+// Webhooks is a stub atoship.WebhooksAPI
+type Webhooks struct {
+	CreateFunc              func(ctx context.Context, req *atoship.CreateWebhookRequest) (*atoship.Webhook, error)
+	ListFunc                func(ctx context.Context) ([]atoship.Webhook, error)
+	ListPagerFunc           func(opts *atoship.ListOptions) *atoship.Pager[atoship.Webhook]
+	DeleteFunc              func(ctx context.Context, webhookID string) error
+	GetFunc                 func(ctx context.Context, webhookID string) (*atoship.Webhook, error)
+	UpdateFunc              func(ctx context.Context, webhookID string, req *atoship.UpdateWebhookRequest) (*atoship.Webhook, error)
+	SetActiveFunc           func(ctx context.Context, webhookID string, active bool) (*atoship.Webhook, error)
+	RotateSecretFunc        func(ctx context.Context, webhookID string) (string, error)
+	SendTestFunc            func(ctx context.Context, webhookID string, eventType string) (*atoship.WebhookDelivery, error)
+	ListDeliveriesFunc      func(ctx context.Context, webhookID string) ([]atoship.WebhookDelivery, error)
+	ListDeliveriesPagerFunc func(webhookID string, opts *atoship.ListOptions) *atoship.Pager[atoship.WebhookDelivery]
+	RetryDeliveryFunc       func(ctx context.Context, webhookID, deliveryID string) (*atoship.WebhookDelivery, error)
+}
+
+// Create calls CreateFunc
+func (m *Webhooks) Create(ctx context.Context, req *atoship.CreateWebhookRequest) (*atoship.Webhook, error) {
+	if m.CreateFunc == nil {
+		return nil, notStubbed("Webhooks.Create")
+	}
+	return m.CreateFunc(ctx, req)
+}
+
+// List calls ListFunc
+func (m *Webhooks) List(ctx context.Context) ([]atoship.Webhook, error) {
+	if m.ListFunc == nil {
+		return nil, notStubbed("Webhooks.List")
+	}
+	return m.ListFunc(ctx)
+}
+
+// ListPager calls ListPagerFunc
+func (m *Webhooks) ListPager(opts *atoship.ListOptions) *atoship.Pager[atoship.Webhook] {
+	if m.ListPagerFunc == nil {
+		return atoship.NewPager(func(context.Context, int, int) (*atoship.Page[atoship.Webhook], error) {
+			return nil, notStubbed("Webhooks.ListPager")
+		}, nil)
+	}
+	return m.ListPagerFunc(opts)
+}
+
+// Delete calls DeleteFunc
+func (m *Webhooks) Delete(ctx context.Context, webhookID string) error {
+	if m.DeleteFunc == nil {
+		return notStubbed("Webhooks.Delete")
+	}
+	return m.DeleteFunc(ctx, webhookID)
+}
+
+// Get calls GetFunc
+func (m *Webhooks) Get(ctx context.Context, webhookID string) (*atoship.Webhook, error) {
+	if m.GetFunc == nil {
+		return nil, notStubbed("Webhooks.Get")
+	}
+	return m.GetFunc(ctx, webhookID)
+}
+
+// Update calls UpdateFunc
+func (m *Webhooks) Update(ctx context.Context, webhookID string, req *atoship.UpdateWebhookRequest) (*atoship.Webhook, error) {
+	if m.UpdateFunc == nil {
+		return nil, notStubbed("Webhooks.Update")
+	}
+	return m.UpdateFunc(ctx, webhookID, req)
+}
+
+// SetActive calls SetActiveFunc
+func (m *Webhooks) SetActive(ctx context.Context, webhookID string, active bool) (*atoship.Webhook, error) {
+	if m.SetActiveFunc == nil {
+		return nil, notStubbed("Webhooks.SetActive")
+	}
+	return m.SetActiveFunc(ctx, webhookID, active)
+}
+
+// RotateSecret calls RotateSecretFunc
+func (m *Webhooks) RotateSecret(ctx context.Context, webhookID string) (string, error) {
+	if m.RotateSecretFunc == nil {
+		return "", notStubbed("Webhooks.RotateSecret")
+	}
+	return m.RotateSecretFunc(ctx, webhookID)
+}
+
+// SendTest calls SendTestFunc
+func (m *Webhooks) SendTest(ctx context.Context, webhookID string, eventType string) (*atoship.WebhookDelivery, error) {
+	if m.SendTestFunc == nil {
+		return nil, notStubbed("Webhooks.SendTest")
+	}
+	return m.SendTestFunc(ctx, webhookID, eventType)
+}
+
+// ListDeliveries calls ListDeliveriesFunc
+func (m *Webhooks) ListDeliveries(ctx context.Context, webhookID string) ([]atoship.WebhookDelivery, error) {
+	if m.ListDeliveriesFunc == nil {
+		return nil, notStubbed("Webhooks.ListDeliveries")
+	}
+	return m.ListDeliveriesFunc(ctx, webhookID)
+}
+
+// ListDeliveriesPager calls ListDeliveriesPagerFunc
+func (m *Webhooks) ListDeliveriesPager(webhookID string, opts *atoship.ListOptions) *atoship.Pager[atoship.WebhookDelivery] {
+	if m.ListDeliveriesPagerFunc == nil {
+		return atoship.NewPager(func(context.Context, int, int) (*atoship.Page[atoship.WebhookDelivery], error) {
+			return nil, notStubbed("Webhooks.ListDeliveriesPager")
+		}, nil)
+	}
+	return m.ListDeliveriesPagerFunc(webhookID, opts)
+}
+
+// RetryDelivery calls RetryDeliveryFunc
+func (m *Webhooks) RetryDelivery(ctx context.Context, webhookID, deliveryID string) (*atoship.WebhookDelivery, error) {
+	if m.RetryDeliveryFunc == nil {
+		return nil, notStubbed("Webhooks.RetryDelivery")
+	}
+	return m.RetryDeliveryFunc(ctx, webhookID, deliveryID)
+}
+
+var (
+	_ atoship.OrdersAPI    = (*Orders)(nil)
+	_ atoship.AddressesAPI = (*Addresses)(nil)
+	_ atoship.ShippingAPI  = (*Shipping)(nil)
+	_ atoship.TrackingAPI  = (*Tracking)(nil)
+	_ atoship.UsersAPI     = (*Users)(nil)
+	_ atoship.AdminAPI     = (*Admin)(nil)
+	_ atoship.CarriersAPI  = (*Carriers)(nil)
+	_ atoship.WebhooksAPI  = (*Webhooks)(nil)
+)
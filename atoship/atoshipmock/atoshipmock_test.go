@@ -0,0 +1,192 @@
+package atoshipmock_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+	"github.com/atoship-LLC/atoship-go/atoship/atoshipmock"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	errStub     = errors.New("stub error")
+)
+
+type argKey struct{}
+
+// stub is one service stub of an atoshipmock.API
+type stub struct {
+	name string
+	ptr  reflect.Value
+}
+
+func stubs(m *atoshipmock.API) []stub {
+	v := reflect.ValueOf(m).Elem()
+	var out []stub
+	for i := 0; i < v.NumField(); i++ {
+		out = append(out, stub{v.Type().Field(i).Name, v.Field(i).Addr()})
+	}
+	return out
+}
+
+// value returns a distinct non-zero value of type t, so a stub forwarding
+// the wrong argument or result is caught
+func value(t *testing.T, typ reflect.Type, i int) reflect.Value {
+	t.Helper()
+	switch {
+	case typ == contextType:
+		return reflect.ValueOf(context.WithValue(context.Background(), argKey{}, i))
+	case typ == errorType:
+		return reflect.ValueOf(&errStub).Elem()
+	}
+	switch typ.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(fmt.Sprintf("value%d", i)).Convert(typ)
+	case reflect.Bool:
+		return reflect.ValueOf(true)
+	case reflect.Pointer:
+		return reflect.New(typ.Elem())
+	case reflect.Slice:
+		return reflect.MakeSlice(typ, 1, 1)
+	case reflect.Func:
+		return reflect.MakeFunc(typ, func([]reflect.Value) []reflect.Value {
+			t.Error("stub called a callback argument")
+			return zeros(typ)
+		})
+	}
+	t.Fatalf("no test value for %v", typ)
+	return reflect.Value{}
+}
+
+func zeros(fn reflect.Type) []reflect.Value {
+	var out []reflect.Value
+	for i := 0; i < fn.NumOut(); i++ {
+		out = append(out, reflect.Zero(fn.Out(i)))
+	}
+	return out
+}
+
+// same reports whether a and b are the same value, comparing funcs and
+// slices by identity
+func same(a, b reflect.Value) bool {
+	switch a.Kind() {
+	case reflect.Func, reflect.Slice:
+		return a.Pointer() == b.Pointer() && (a.Kind() != reflect.Slice || a.Len() == b.Len())
+	}
+	return a.Interface() == b.Interface()
+}
+
+func TestUnsetFuncReturnsErrNotStubbed(t *testing.T) {
+	for _, s := range stubs(&atoshipmock.API{}) {
+		typ := s.ptr.Type()
+		for i := 0; i < typ.NumMethod(); i++ {
+			method := typ.Method(i)
+			name := s.name + "." + method.Name
+			t.Run(name, func(t *testing.T) {
+				fn := s.ptr.Method(i)
+				var args []reflect.Value
+				for j := 0; j < fn.Type().NumIn(); j++ {
+					args = append(args, value(t, fn.Type().In(j), j))
+				}
+				results := fn.Call(args)
+				last := results[len(results)-1]
+
+				// Pager methods fail on the first page instead
+				if next := last.MethodByName("Next"); last.Kind() == reflect.Pointer && next.IsValid() {
+					if last.IsNil() {
+						t.Fatal("returned a nil pager")
+					}
+					results = next.Call([]reflect.Value{reflect.ValueOf(context.Background())})
+					last = results[len(results)-1]
+				}
+				if last.Type() != errorType {
+					t.Fatalf("last result is a %v, want an error", last.Type())
+				}
+				err, _ := last.Interface().(error)
+				if !errors.Is(err, atoshipmock.ErrNotStubbed) {
+					t.Fatalf("err = %v, want ErrNotStubbed", err)
+				}
+				if !strings.Contains(err.Error(), name) {
+					t.Errorf("err = %q, want it to name %s", err, name)
+				}
+				for _, r := range results[:len(results)-1] {
+					if !r.IsZero() {
+						t.Errorf("result %v alongside the error, want zero", r)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestSetFuncReceivesArgs(t *testing.T) {
+	m := &atoshipmock.API{}
+	for _, s := range stubs(m) {
+		typ := s.ptr.Type()
+		for i := 0; i < typ.NumMethod(); i++ {
+			method := typ.Method(i)
+			t.Run(s.name+"."+method.Name, func(t *testing.T) {
+				field := s.ptr.Elem().FieldByName(method.Name + "Func")
+				if !field.IsValid() {
+					t.Fatalf("%s has no %sFunc field", s.name, method.Name)
+				}
+
+				var got []reflect.Value
+				var want []reflect.Value
+				for j := 0; j < field.Type().NumOut(); j++ {
+					want = append(want, value(t, field.Type().Out(j), 100+j))
+				}
+				field.Set(reflect.MakeFunc(field.Type(), func(in []reflect.Value) []reflect.Value {
+					got = in
+					return want
+				}))
+
+				fn := s.ptr.Method(i)
+				var args []reflect.Value
+				for j := 0; j < fn.Type().NumIn(); j++ {
+					args = append(args, value(t, fn.Type().In(j), j))
+				}
+				results := fn.Call(args)
+
+				if len(got) != len(args) {
+					t.Fatalf("Func got %d arguments, want %d", len(got), len(args))
+				}
+				for j := range args {
+					if !same(got[j], args[j]) {
+						t.Errorf("argument %d = %v, want %v", j, got[j], args[j])
+					}
+				}
+				for j := range want {
+					if !same(results[j], want[j]) {
+						t.Errorf("result %d = %v, want the Func's %v", j, results[j], want[j])
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestStubsAsAPI(t *testing.T) {
+	stubs := &atoshipmock.API{}
+	stubs.Tracking.TrackFunc = func(ctx context.Context, n string) (*atoship.TrackingInfo, error) {
+		return &atoship.TrackingInfo{TrackingNumber: n, Delivered: true}, nil
+	}
+	api := stubs.API()
+
+	info, err := api.Tracking.Track(context.Background(), "1Z999")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.TrackingNumber != "1Z999" || !info.Delivered {
+		t.Errorf("Track = %+v", info)
+	}
+	if _, err := api.Orders.Get(context.Background(), "ord_1"); !errors.Is(err, atoshipmock.ErrNotStubbed) {
+		t.Errorf("Orders.Get: err = %v, want ErrNotStubbed", err)
+	}
+}
@@ -0,0 +1,374 @@
+package atoshiptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+// Mode selects whether a Recorder records or replays interactions
+type Mode int
+
+const (
+	// ModeReplay serves responses from the cassette and fails any request
+	// that was not recorded
+	ModeReplay Mode = iota
+	// ModeRecord forwards requests to the API and writes every interaction
+	// to the cassette on Stop
+	ModeRecord
+	// ModeAuto replays if the cassette exists and records otherwise
+	ModeAuto
+)
+
+// redacted replaces secrets in recorded interactions
+const redacted = "[REDACTED]"
+
+// recordedHeaders are the response headers kept in a cassette
+var recordedHeaders = []string{
+	"Content-Type",
+	"Retry-After",
+	"X-Request-Id",
+	"X-RateLimit-Limit",
+	"X-RateLimit-Remaining",
+	"X-RateLimit-Reset",
+}
+
+// Interaction is a recorded request and the response it received
+type Interaction struct {
+	Method         string            `json:"method"`
+	Path           string            `json:"path"`
+	RequestBody    json.RawMessage   `json:"requestBody,omitempty"`
+	Status         int               `json:"status"`
+	ResponseHeader map[string]string `json:"responseHeader,omitempty"`
+	Response       json.RawMessage   `json:"response"`
+}
+
+// Cassette is the on-disk format of recorded interactions
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Recorder records API interactions to a cassette file, or replays them,
+// through client middleware:
+//
+//	rec, err := atoshiptest.NewRecorder("testdata/purchase.json", atoshiptest.ModeAuto)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer func() {
+//		if err := rec.Stop(); err != nil {
+//			t.Error(err)
+//		}
+//	}()
+//	client := atoship.NewClient(apiKey, rec.Option())
+//
+// API keys are never written to the cassette, and webhook secrets in
+// response bodies are redacted.
+type Recorder struct {
+	path string
+	mode Mode
+
+	mu        sync.Mutex
+	cassette  Cassette
+	used      []bool
+	unmatched []string
+	secrets   []string
+}
+
+// NewRecorder returns a Recorder backed by the cassette at path. In replay
+// mode the cassette must already exist.
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{path: path, mode: mode}
+
+	if mode == ModeAuto {
+		r.mode = ModeRecord
+		if _, err := os.Stat(path); err == nil {
+			r.mode = ModeReplay
+		}
+	}
+
+	if r.mode == ModeReplay {
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("atoshiptest: reading cassette: %w", err)
+		}
+		if err := json.Unmarshal(raw, &r.cassette); err != nil {
+			return nil, fmt.Errorf("atoshiptest: parsing cassette %s: %w", path, err)
+		}
+		for i := range r.cassette.Interactions {
+			in := &r.cassette.Interactions[i]
+			in.RequestBody = compactJSON(in.RequestBody)
+			in.Response = compactJSON(in.Response)
+		}
+		r.used = make([]bool, len(r.cassette.Interactions))
+	}
+	return r, nil
+}
+
+// Mode returns the mode the Recorder is operating in
+func (r *Recorder) Mode() Mode {
+	return r.mode
+}
+
+// Option returns a client option installing the Recorder
+func (r *Recorder) Option() atoship.ClientOption {
+	return atoship.WithMiddleware(r.Middleware)
+}
+
+// Middleware records or replays requests passing through the client
+func (r *Recorder) Middleware(next atoship.RoundTripFunc) atoship.RoundTripFunc {
+	return func(req *http.Request) (*http.Response, error) {
+		body, err := readBody(req)
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+
+		if r.mode == ModeReplay {
+			return r.replay(req, body)
+		}
+		return r.record(next, req, body)
+	}
+}
+
+// record forwards the request and stores the interaction
+func (r *Recorder) record(next atoship.RoundTripFunc, req *http.Request, body []byte) (*http.Response, error) {
+	resp, err := next(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := map[string]string{}
+	for _, name := range recordedHeaders {
+		if v := resp.Header.Get(name); v != "" {
+			header[name] = v
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key := req.Header.Get("X-API-Key"); key != "" {
+		r.addSecret(key)
+	}
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Method:         req.Method,
+		Path:           requestPath(req),
+		RequestBody:    compactJSON(body),
+		Status:         resp.StatusCode,
+		ResponseHeader: header,
+		Response:       compactJSON(respBody),
+	})
+	return resp, nil
+}
+
+// replay serves the first unused interaction matching the request
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	path := requestPath(req)
+	want := compactJSON(body)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.cassette.Interactions {
+		if r.used[i] || in.Method != req.Method || in.Path != path || !bytes.Equal(in.RequestBody, want) {
+			continue
+		}
+		r.used[i] = true
+
+		resp := &http.Response{
+			StatusCode: in.Status,
+			Status:     fmt.Sprintf("%d %s", in.Status, http.StatusText(in.Status)),
+			Proto:      "HTTP/1.1",
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+			Header:     http.Header{},
+			Body:       io.NopCloser(bytes.NewReader(in.Response)),
+			Request:    req,
+		}
+		for k, v := range in.ResponseHeader {
+			resp.Header.Set(k, v)
+		}
+		resp.ContentLength = int64(len(in.Response))
+		return resp, nil
+	}
+
+	err := &UnmatchedError{Cassette: r.path, Method: req.Method, Path: path, Body: want}
+	r.unmatched = append(r.unmatched, err.request())
+	return nil, err
+}
+
+// UnmatchedError is returned for a replayed request that has no recorded
+// interaction. SDK calls surface it through errors.As on the returned error.
+type UnmatchedError struct {
+	Cassette string
+	Method   string
+	Path     string
+	Body     json.RawMessage
+}
+
+// Error implements the error interface
+func (e *UnmatchedError) Error() string {
+	return fmt.Sprintf("atoshiptest: no recorded interaction in %s for %s", e.Cassette, e.request())
+}
+
+func (e *UnmatchedError) request() string {
+	desc := e.Method + " " + e.Path
+	if len(e.Body) > 0 {
+		desc += " " + string(e.Body)
+	}
+	return desc
+}
+
+// Stop finishes the session. In record mode it writes the cassette; in
+// replay mode it reports requests that had no recorded interaction.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == ModeReplay {
+		if len(r.unmatched) > 0 {
+			return fmt.Errorf("atoshiptest: %d unmatched request(s) replaying %s:\n  %s",
+				len(r.unmatched), r.path, strings.Join(r.unmatched, "\n  "))
+		}
+		return nil
+	}
+
+	for i := range r.cassette.Interactions {
+		r.redact(&r.cassette.Interactions[i])
+	}
+	raw, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, append(raw, '\n'), 0o644)
+}
+
+// addSecret registers a value that must never reach the cassette. The
+// caller must hold r.mu.
+func (r *Recorder) addSecret(secret string) {
+	for _, s := range r.secrets {
+		if s == secret {
+			return
+		}
+	}
+	r.secrets = append(r.secrets, secret)
+}
+
+// redact strips secrets from an interaction
+func (r *Recorder) redact(in *Interaction) {
+	in.Path = r.redactString(in.Path)
+	in.RequestBody = r.redactJSON(in.RequestBody)
+	in.Response = r.redactJSON(in.Response)
+	for k, v := range in.ResponseHeader {
+		in.ResponseHeader[k] = r.redactString(v)
+	}
+}
+
+func (r *Recorder) redactString(s string) string {
+	for _, secret := range r.secrets {
+		s = strings.ReplaceAll(s, secret, redacted)
+	}
+	return s
+}
+
+// redactJSON replaces known secrets and the values of secret-like fields
+func (r *Recorder) redactJSON(raw json.RawMessage) json.RawMessage {
+	if len(raw) == 0 {
+		return raw
+	}
+	v, err := decodeJSON(raw)
+	if err != nil {
+		return json.RawMessage(r.redactString(string(raw)))
+	}
+	out, err := json.Marshal(r.redactValue(v))
+	if err != nil {
+		return raw
+	}
+	return out
+}
+
+func (r *Recorder) redactValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if isSecretField(k) {
+				if _, ok := child.(string); ok {
+					val[k] = redacted
+					continue
+				}
+			}
+			val[k] = r.redactValue(child)
+		}
+		return val
+	case []any:
+		for i, child := range val {
+			val[i] = r.redactValue(child)
+		}
+		return val
+	case string:
+		return r.redactString(val)
+	}
+	return v
+}
+
+// isSecretField reports whether a JSON field holds a credential
+func isSecretField(name string) bool {
+	switch strings.ToLower(name) {
+	case "secret", "apikey", "api_key", "password", "token":
+		return true
+	}
+	return false
+}
+
+// requestPath returns the path and query of a request
+func requestPath(req *http.Request) string {
+	if req.URL.RawQuery == "" {
+		return req.URL.Path
+	}
+	return req.URL.Path + "?" + req.URL.RawQuery
+}
+
+// compactJSON normalizes a JSON body, sorting object keys, so equivalent
+// bodies compare equal
+func compactJSON(body []byte) json.RawMessage {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil
+	}
+	v, err := decodeJSON(body)
+	if err != nil {
+		return json.RawMessage(body)
+	}
+	out, err := json.Marshal(v)
+	if err != nil {
+		return json.RawMessage(body)
+	}
+	return out
+}
+
+// decodeJSON decodes a JSON document, preserving numbers exactly
+func decodeJSON(raw []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
@@ -0,0 +1,179 @@
+package atoshiptest_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+	"github.com/atoship-LLC/atoship-go/atoship/atoshiptest"
+)
+
+func TestRecorderRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "testdata", "orders.json")
+
+	rec, err := atoshiptest.NewRecorder(path, atoshiptest.ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != atoshiptest.ModeRecord {
+		t.Fatalf("Mode = %v, want ModeRecord for a missing cassette", rec.Mode())
+	}
+	srv := atoshiptest.NewServer()
+	client := srv.Client(rec.Option())
+	created, err := client.Orders.Create(ctx, newOrder(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Orders.Get(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Orders.Get(ctx, "ord_missing"); !errors.Is(err, atoship.ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	rec, err = atoshiptest.NewRecorder(path, atoshiptest.ModeAuto)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Mode() != atoshiptest.ModeReplay {
+		t.Fatalf("Mode = %v, want ModeReplay for an existing cassette", rec.Mode())
+	}
+	// The server is gone, so every response must come from the cassette
+	client = atoship.NewClient(atoshiptest.DefaultAPIKey, atoship.WithBaseURL(srv.URL), rec.Option())
+	replayed, err := client.Orders.Create(ctx, newOrder(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed.ID != created.ID || replayed.RecipientName != created.RecipientName {
+		t.Errorf("replayed order = %+v, want %+v", replayed, created)
+	}
+	got, err := client.Orders.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.OrderNumber != "ORD-0001" {
+		t.Errorf("OrderNumber = %q", got.OrderNumber)
+	}
+	if _, err := client.Orders.Get(ctx, "ord_missing"); !errors.Is(err, atoship.ErrNotFound) {
+		t.Errorf("err = %v, want the recorded ErrNotFound", err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Errorf("Stop: %v", err)
+	}
+}
+
+func TestRecorderUnmatchedRequest(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "carriers.json")
+
+	rec, err := atoshiptest.NewRecorder(path, atoshiptest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, srv := atoshiptest.NewClient(rec.Option())
+	if _, err := client.Carriers.List(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	srv.Close()
+
+	if _, err := atoshiptest.NewRecorder(filepath.Join(t.TempDir(), "missing.json"), atoshiptest.ModeReplay); err == nil {
+		t.Error("NewRecorder replaying a missing cassette succeeded")
+	}
+
+	rec, err = atoshiptest.NewRecorder(path, atoshiptest.ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = atoship.NewClient(atoshiptest.DefaultAPIKey, atoship.WithBaseURL(srv.URL), rec.Option())
+	if _, err := client.Carriers.List(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Each interaction is replayed once
+	_, err = client.Carriers.List(ctx)
+	var unmatched *atoshiptest.UnmatchedError
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("err = %v, want *UnmatchedError", err)
+	}
+	if unmatched.Method != "GET" || !strings.HasPrefix(unmatched.Path, "/api/carriers") || unmatched.Cassette != path {
+		t.Errorf("UnmatchedError = %+v", unmatched)
+	}
+
+	_, err = client.Users.GetProfile(ctx)
+	if !errors.As(err, &unmatched) {
+		t.Fatalf("err = %v, want *UnmatchedError", err)
+	}
+
+	err = rec.Stop()
+	if err == nil {
+		t.Fatal("Stop reported no unmatched requests")
+	}
+	for _, want := range []string{"2 unmatched", "GET /api/carriers", "GET /api/profile"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("Stop error %q does not mention %q", err, want)
+		}
+	}
+}
+
+func TestRecorderRedactsSecrets(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "webhooks.json")
+
+	rec, err := atoshiptest.NewRecorder(path, atoshiptest.ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, srv := atoshiptest.NewClient(rec.Option())
+	defer srv.Close()
+
+	hook, err := client.Webhooks.Create(ctx, &atoship.CreateWebhookRequest{
+		URL:    "https://example.com/hooks",
+		Events: []string{atoship.EventOrderCreated},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := client.Webhooks.RotateSecret(ctx, hook.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hook.Secret == "" || rotated == "" {
+		t.Fatalf("fake returned no secrets: %q, %q", hook.Secret, rotated)
+	}
+	if _, err := client.Webhooks.List(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cassette := string(raw)
+	for name, secret := range map[string]string{
+		"API key":        atoshiptest.DefaultAPIKey,
+		"webhook secret": hook.Secret,
+		"rotated secret": rotated,
+	} {
+		if strings.Contains(cassette, secret) {
+			t.Errorf("cassette contains the %s", name)
+		}
+	}
+	if !strings.Contains(cassette, hook.ID) || !strings.Contains(cassette, "[REDACTED]") {
+		t.Errorf("cassette is missing the recorded webhook:\n%s", cassette)
+	}
+}
@@ -0,0 +1,109 @@
+package atoshiptest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+	"github.com/atoship-LLC/atoship-go/atoship/webhooks"
+)
+
+// deliveryTimeout bounds a single webhook delivery
+const deliveryTimeout = 5 * time.Second
+
+// deliver POSTs a signed event to a webhook's URL and records the attempt
+func (s *Server) deliver(ctx context.Context, wh *atoship.Webhook, eventID, eventType string, payload []byte, attempt int) atoship.WebhookDelivery {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	started := time.Now()
+	d := atoship.WebhookDelivery{
+		WebhookID: wh.ID,
+		EventID:   eventID,
+		EventType: eventType,
+		Attempt:   attempt,
+		CreatedAt: started.UTC(),
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(payload))
+	if err == nil {
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(webhooks.SignatureHeader, webhooks.Sign(payload, wh.Secret, started))
+		var resp *http.Response
+		resp, err = http.DefaultClient.Do(req)
+		if err == nil {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+			d.StatusCode = resp.StatusCode
+			d.ResponseBody = string(body)
+			d.Success = resp.StatusCode >= 200 && resp.StatusCode < 300
+		}
+	}
+	if err != nil {
+		d.Error = err.Error()
+	}
+	d.DurationMs = time.Since(started).Milliseconds()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	d.ID = s.nextID("whd")
+	s.delivery = append(s.delivery, &d)
+	s.payloads[d.ID] = payload
+	return d
+}
+
+// sampleEvent returns the JSON payload of a test event of the given type
+func sampleEvent(eventID, eventType string) ([]byte, error) {
+	now := time.Now().UTC()
+	var data any
+	switch {
+	case strings.HasPrefix(eventType, "order."):
+		data = atoship.Order{
+			ID:               "ord_test",
+			OrderNumber:      "TEST-1001",
+			Status:           "pending",
+			RecipientName:    "Test Recipient",
+			RecipientStreet1: "1 Test St",
+			RecipientCity:    "San Francisco",
+			RecipientState:   "CA",
+			RecipientPostal:  "94105",
+			RecipientCountry: "US",
+			CreatedAt:        now,
+			UpdatedAt:        now,
+		}
+	case strings.HasPrefix(eventType, "label."):
+		data = atoship.ShippingLabel{
+			ID:             "lbl_test",
+			TrackingNumber: "TEST000000000001",
+			Carrier:        "USPS",
+			Service:        "Priority",
+			Rate:           7.5,
+			CreatedAt:      now,
+		}
+	case strings.HasPrefix(eventType, "tracking."):
+		data = atoship.TrackingInfo{
+			TrackingNumber: "TEST000000000001",
+			Carrier:        "USPS",
+			Status:         "in_transit",
+		}
+	default:
+		return nil, fmt.Errorf("unknown event type %q", eventType)
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(webhooks.Event{
+		ID:        eventID,
+		Type:      eventType,
+		CreatedAt: now,
+		Data:      raw,
+	})
+}
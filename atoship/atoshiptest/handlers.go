@@ -0,0 +1,789 @@
+package atoshiptest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+// readBody reads and closes a request body
+func readBody(r *http.Request) ([]byte, error) {
+	if r.Body == nil {
+		return nil, nil
+	}
+	defer r.Body.Close()
+	return io.ReadAll(r.Body)
+}
+
+// gunzip decompresses a gzip-encoded body
+func gunzip(body []byte) ([]byte, error) {
+	zr, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	return io.ReadAll(zr)
+}
+
+// merge overlays the non-empty JSON fields of patch onto dst
+func merge(dst, patch any) {
+	raw, err := json.Marshal(patch)
+	if err != nil {
+		return
+	}
+	json.Unmarshal(raw, dst)
+}
+
+func (s *Server) handleOrders(w http.ResponseWriter, r *http.Request, parts []string, body []byte) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		s.listOrders(w, r)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		var req atoship.CreateOrderRequest
+		if !decode(w, body, &req) {
+			return
+		}
+		if fields := validateOrder(&req); len(fields) > 0 {
+			writeValidation(w, fields)
+			return
+		}
+		s.mu.Lock()
+		order := s.createOrder(&req)
+		s.mu.Unlock()
+		writeData(w, http.StatusCreated, order)
+	case len(parts) == 1 && parts[0] == "batch" && r.Method == http.MethodPost:
+		var req struct {
+			Orders []*atoship.CreateOrderRequest `json:"orders"`
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		resp := atoship.BulkCreateResponse{
+			Successful: []atoship.Order{},
+			Failed:     []atoship.FailedOrder{},
+		}
+		s.mu.Lock()
+		for _, o := range req.Orders {
+			if fields := validateOrder(o); len(fields) > 0 {
+				resp.Failed = append(resp.Failed, atoship.FailedOrder{Order: *o, Error: describeFields(fields)})
+				continue
+			}
+			resp.Successful = append(resp.Successful, *s.createOrder(o))
+		}
+		s.mu.Unlock()
+		writeData(w, http.StatusOK, resp)
+	case len(parts) == 1:
+		s.handleOrder(w, r, parts[0], body)
+	case len(parts) == 2 && r.Method == http.MethodPost && (parts[1] == "ship" || parts[1] == "cancel"):
+		var req map[string]string
+		if !decode(w, body, &req) {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		order := s.findOrder(parts[0])
+		if order == nil {
+			notFound(w, "order", parts[0])
+			return
+		}
+		now := time.Now().UTC()
+		if parts[1] == "ship" {
+			order.Status = "shipped"
+			order.TrackingNumber = req["trackingNumber"]
+			order.CarrierService = req["carrier"]
+			order.ShippedAt = &now
+		} else {
+			if order.Status == "shipped" || order.Status == "delivered" {
+				writeError(w, http.StatusConflict, atoship.ErrCodeValidation, "order has already shipped")
+				return
+			}
+			order.Status = "cancelled"
+			if reason := req["reason"]; reason != "" {
+				order.Notes = reason
+			}
+		}
+		order.UpdatedAt = now
+		writeData(w, http.StatusOK, order)
+	default:
+		writeError(w, http.StatusNotFound, atoship.ErrCodeNotFound, "unknown endpoint")
+	}
+}
+
+func (s *Server) handleOrder(w http.ResponseWriter, r *http.Request, id string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	order := s.findOrder(id)
+	if order == nil {
+		notFound(w, "order", id)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeData(w, http.StatusOK, order)
+	case http.MethodPut:
+		var req atoship.UpdateOrderRequest
+		if !decode(w, body, &req) {
+			return
+		}
+		merge(order, &req)
+		order.UpdatedAt = time.Now().UTC()
+		writeData(w, http.StatusOK, order)
+	case http.MethodDelete:
+		for i, o := range s.orders {
+			if o.ID == id {
+				s.orders = append(s.orders[:i], s.orders[i+1:]...)
+				break
+			}
+		}
+		writeData(w, http.StatusOK, nil)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) listOrders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	status, source := q.Get("status"), q.Get("source")
+	search := strings.ToLower(q.Get("search"))
+
+	s.mu.Lock()
+	var matched []atoship.Order
+	for _, o := range s.orders {
+		if status != "" && !strings.EqualFold(o.Status, status) {
+			continue
+		}
+		if source != "" && !strings.EqualFold(o.Source, source) {
+			continue
+		}
+		if search != "" &&
+			!strings.Contains(strings.ToLower(o.OrderNumber), search) &&
+			!strings.Contains(strings.ToLower(o.RecipientName), search) {
+			continue
+		}
+		matched = append(matched, *o)
+	}
+	s.mu.Unlock()
+
+	if q.Get("sortOrder") == "desc" {
+		sort.SliceStable(matched, func(i, j int) bool {
+			return matched[i].CreatedAt.After(matched[j].CreatedAt)
+		})
+	}
+
+	page, limit := paging(r, 20)
+	start, end, hasMore := pageBounds(len(matched), page, limit)
+	writeData(w, http.StatusOK, atoship.OrderListResponse{
+		Orders:  append([]atoship.Order{}, matched[start:end]...),
+		Total:   len(matched),
+		Page:    page,
+		Limit:   limit,
+		HasMore: hasMore,
+	})
+}
+
+// createOrder stores a new order. The caller must hold s.mu.
+func (s *Server) createOrder(req *atoship.CreateOrderRequest) *atoship.Order {
+	order := &atoship.Order{}
+	merge(order, req)
+
+	now := time.Now().UTC()
+	order.ID = s.nextID("ord")
+	order.Status = "pending"
+	order.CreatedAt = now
+	order.UpdatedAt = now
+	if order.Source == "" {
+		order.Source = "api"
+	}
+	if order.Currency == "" {
+		order.Currency = "USD"
+	}
+	if order.WeightUnit == "" {
+		order.WeightUnit = "lb"
+	}
+	for _, item := range order.Items {
+		order.TotalWeight += item.Weight * float64(item.Quantity)
+		order.TotalValue += item.UnitPrice * float64(item.Quantity)
+	}
+
+	s.orders = append(s.orders, order)
+	return order
+}
+
+// findOrder returns the stored order with id. The caller must hold s.mu.
+func (s *Server) findOrder(id string) *atoship.Order {
+	for _, o := range s.orders {
+		if o.ID == id {
+			return o
+		}
+	}
+	return nil
+}
+
+// validateOrder returns the missing required fields of an order
+func validateOrder(req *atoship.CreateOrderRequest) map[string]string {
+	fields := map[string]string{}
+	if req.OrderNumber == "" {
+		fields["orderNumber"] = "is required"
+	}
+	if req.RecipientName == "" {
+		fields["recipientName"] = "is required"
+	}
+	if req.RecipientStreet1 == "" {
+		fields["recipientStreet1"] = "is required"
+	}
+	if req.RecipientCity == "" {
+		fields["recipientCity"] = "is required"
+	}
+	if req.RecipientPostal == "" {
+		fields["recipientPostalCode"] = "is required"
+	}
+	if req.RecipientCountry == "" {
+		fields["recipientCountry"] = "is required"
+	}
+	return fields
+}
+
+// describeFields formats validation failures as a single message
+func describeFields(fields map[string]string) string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	msgs := make([]string, len(names))
+	for i, name := range names {
+		msgs[i] = name + " " + fields[name]
+	}
+	return strings.Join(msgs, "; ")
+}
+
+func (s *Server) handleAddresses(w http.ResponseWriter, r *http.Request, parts []string, body []byte) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		s.mu.Lock()
+		items := make([]atoship.Address, len(s.addresses))
+		for i, a := range s.addresses {
+			items[i] = *a
+		}
+		s.mu.Unlock()
+		writePage(w, r, items)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		var addr atoship.Address
+		if !decode(w, body, &addr) {
+			return
+		}
+		if fields := validateAddress(&addr); len(fields) > 0 {
+			writeValidation(w, fields)
+			return
+		}
+		s.mu.Lock()
+		addr.ID = s.nextID("adr")
+		s.addresses = append(s.addresses, &addr)
+		s.mu.Unlock()
+		writeData(w, http.StatusCreated, addr)
+	case len(parts) == 1 && parts[0] == "validate" && r.Method == http.MethodPost:
+		var req atoship.ValidateAddressRequest
+		if !decode(w, body, &req) {
+			return
+		}
+		addr := atoship.Address{
+			Name:       req.Name,
+			Company:    req.Company,
+			Street1:    req.Street1,
+			Street2:    req.Street2,
+			City:       req.City,
+			State:      strings.ToUpper(req.State),
+			PostalCode: req.PostalCode,
+			Country:    strings.ToUpper(req.Country),
+		}
+		resp := atoship.ValidateAddressResponse{}
+		if fields := validateAddress(&addr); len(fields) > 0 {
+			resp.Errors = strings.Split(describeFields(fields), "; ")
+		} else {
+			addr.Validated = true
+			resp.IsValid = true
+			resp.Address = &addr
+		}
+		writeData(w, http.StatusOK, resp)
+	case len(parts) == 1:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		idx := -1
+		for i, a := range s.addresses {
+			if a.ID == parts[0] {
+				idx = i
+			}
+		}
+		if idx < 0 {
+			notFound(w, "address", parts[0])
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			writeData(w, http.StatusOK, s.addresses[idx])
+		case http.MethodPut:
+			var addr atoship.Address
+			if !decode(w, body, &addr) {
+				return
+			}
+			merge(s.addresses[idx], &addr)
+			s.addresses[idx].ID = parts[0]
+			writeData(w, http.StatusOK, s.addresses[idx])
+		case http.MethodDelete:
+			s.addresses = append(s.addresses[:idx], s.addresses[idx+1:]...)
+			writeData(w, http.StatusOK, nil)
+		default:
+			methodNotAllowed(w)
+		}
+	default:
+		writeError(w, http.StatusNotFound, atoship.ErrCodeNotFound, "unknown endpoint")
+	}
+}
+
+func (s *Server) handleAddressSearch(w http.ResponseWriter, r *http.Request) {
+	query := strings.ToLower(r.URL.Query().Get("q"))
+	country := r.URL.Query().Get("country")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	matches := []atoship.Address{}
+	for _, a := range s.addresses {
+		if country != "" && !strings.EqualFold(a.Country, country) {
+			continue
+		}
+		text := strings.ToLower(a.Name + " " + a.Company + " " + a.Street1 + " " + a.City)
+		if strings.Contains(text, query) {
+			matches = append(matches, *a)
+		}
+	}
+	writeData(w, http.StatusOK, matches)
+}
+
+// validateAddress returns the missing required fields of an address
+func validateAddress(addr *atoship.Address) map[string]string {
+	fields := map[string]string{}
+	if addr.Street1 == "" {
+		fields["street1"] = "is required"
+	}
+	if addr.City == "" {
+		fields["city"] = "is required"
+	}
+	if addr.PostalCode == "" {
+		fields["postalCode"] = "is required"
+	}
+	if addr.Country == "" {
+		fields["country"] = "is required"
+	}
+	return fields
+}
+
+func (s *Server) handleCarriers(w http.ResponseWriter, r *http.Request, parts []string, body []byte) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		s.mu.Lock()
+		items := append([]atoship.Carrier(nil), s.carriers...)
+		s.mu.Unlock()
+		writePage(w, r, items)
+	case len(parts) == 1 && parts[0] == "smart-rates" && r.Method == http.MethodPost:
+		var req atoship.RateRequest
+		if !decode(w, body, &req) {
+			return
+		}
+		fields := map[string]string{}
+		if req.FromAddress == nil {
+			fields["fromAddress"] = "is required"
+		}
+		if req.ToAddress == nil {
+			fields["toAddress"] = "is required"
+		}
+		if req.Parcel == nil || req.Parcel.Weight <= 0 {
+			fields["parcel.weight"] = "must be positive"
+		}
+		if len(fields) > 0 {
+			writeValidation(w, fields)
+			return
+		}
+		s.mu.Lock()
+		rates := s.quoteRates(req.Parcel.Weight)
+		s.mu.Unlock()
+		writeData(w, http.StatusOK, rates)
+	default:
+		writeError(w, http.StatusNotFound, atoship.ErrCodeNotFound, "unknown endpoint")
+	}
+}
+
+// quoteRates stores and returns a rate per carrier service. The caller
+// must hold s.mu.
+func (s *Server) quoteRates(weight float64) []atoship.ShippingRate {
+	var rates []atoship.ShippingRate
+	for ci, c := range s.carriers {
+		for si, service := range c.Services {
+			days := 5 - 2*si
+			rate := atoship.ShippingRate{
+				ID:           s.nextID("rate"),
+				Carrier:      c.Name,
+				Service:      service,
+				ServiceCode:  strings.ToLower(strings.ReplaceAll(service, " ", "_")),
+				Rate:         float64(int((5+float64(ci)+float64(si)*7+weight*1.25)*100)) / 100,
+				Currency:     "USD",
+				DeliveryDays: days,
+				DeliveryDate: time.Now().UTC().AddDate(0, 0, days).Truncate(24 * time.Hour),
+				Tracking:     true,
+			}
+			s.rates[rate.ID] = &rate
+			rates = append(rates, rate)
+		}
+	}
+	return rates
+}
+
+func (s *Server) handleLabels(w http.ResponseWriter, r *http.Request, parts []string, body []byte) {
+	switch {
+	case len(parts) == 1 && parts[0] == "purchase-v2" && r.Method == http.MethodPost:
+		var req atoship.PurchaseLabelRequest
+		if !decode(w, body, &req) {
+			return
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		rate := s.rates[req.RateID]
+		if rate == nil {
+			writeValidation(w, map[string]string{"rateId": "is unknown or expired"})
+			return
+		}
+		label := &atoship.ShippingLabel{
+			ID:             s.nextID("lbl"),
+			TrackingNumber: fmt.Sprintf("9400%018d", s.seq),
+			Carrier:        rate.Carrier,
+			Service:        rate.Service,
+			Rate:           rate.Rate,
+			CreatedAt:      time.Now().UTC(),
+		}
+		label.LabelURL = s.URL + labelFilePrefix + label.ID + ".pdf"
+		s.labels[label.ID] = label
+		s.tracking[label.TrackingNumber] = &atoship.TrackingInfo{
+			TrackingNumber: label.TrackingNumber,
+			Carrier:        label.Carrier,
+			Status:         "PRE_TRANSIT",
+			Events: []atoship.TrackingEvent{{
+				Timestamp:   label.CreatedAt,
+				Status:      "PRE_TRANSIT",
+				Description: "Shipping label created",
+			}},
+		}
+		if order := s.findOrder(req.OrderID); order != nil {
+			order.TrackingNumber = label.TrackingNumber
+			order.CarrierService = label.Carrier + " " + label.Service
+			order.ShippingCost = label.Rate
+			order.UpdatedAt = label.CreatedAt
+		}
+		writeData(w, http.StatusCreated, label)
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		label := s.labels[parts[0]]
+		if label == nil {
+			notFound(w, "label", parts[0])
+			return
+		}
+		writeData(w, http.StatusOK, label)
+	case len(parts) == 2 && parts[1] == "cancel" && r.Method == http.MethodPost:
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		label := s.labels[parts[0]]
+		if label == nil {
+			notFound(w, "label", parts[0])
+			return
+		}
+		if info := s.tracking[label.TrackingNumber]; info != nil {
+			info.Status = "CANCELLED"
+		}
+		writeData(w, http.StatusOK, label)
+	default:
+		writeError(w, http.StatusNotFound, atoship.ErrCodeNotFound, "unknown endpoint")
+	}
+}
+
+// labelFilePrefix is the path under which label PDFs are served
+const labelFilePrefix = "/labels/"
+
+// serveLabelFile serves the PDF at a label's LabelURL. Like the API's
+// label URLs, it needs no API key.
+func (s *Server) serveLabelFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w)
+		return
+	}
+	id, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, labelFilePrefix), ".pdf")
+	s.mu.Lock()
+	label := s.labels[id]
+	s.mu.Unlock()
+	if !ok || label == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Write(labelPDF(label))
+}
+
+// labelPDF returns a placeholder PDF document for a label
+func labelPDF(l *atoship.ShippingLabel) []byte {
+	return []byte(fmt.Sprintf("%%PDF-1.4\n%% atoshiptest label %s\n%% %s %s %s\n%%%%EOF\n", l.ID, l.Carrier, l.Service, l.TrackingNumber))
+}
+
+func (s *Server) handleTracking(w http.ResponseWriter, r *http.Request, parts []string, body []byte) {
+	switch {
+	case len(parts) == 1 && parts[0] == "batch" && r.Method == http.MethodPost:
+		var req struct {
+			TrackingNumbers []string `json:"trackingNumbers"`
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		s.mu.Lock()
+		infos := []atoship.TrackingInfo{}
+		for _, n := range req.TrackingNumbers {
+			if info := s.tracking[n]; info != nil {
+				infos = append(infos, *info)
+			}
+		}
+		s.mu.Unlock()
+		writeData(w, http.StatusOK, infos)
+	case len(parts) == 1 && r.Method == http.MethodGet:
+		carrier := r.URL.Query().Get("carrier")
+		s.mu.Lock()
+		info := s.tracking[parts[0]]
+		s.mu.Unlock()
+		if info == nil || (carrier != "" && !strings.EqualFold(info.Carrier, carrier)) {
+			notFound(w, "tracking number", parts[0])
+			return
+		}
+		writeData(w, http.StatusOK, info)
+	default:
+		writeError(w, http.StatusNotFound, atoship.ErrCodeNotFound, "unknown endpoint")
+	}
+}
+
+func (s *Server) handleProfile(w http.ResponseWriter, r *http.Request, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	switch r.Method {
+	case http.MethodGet:
+		writeData(w, http.StatusOK, s.profile)
+	case http.MethodPut:
+		var updates atoship.User
+		if !decode(w, body, &updates) {
+			return
+		}
+		id, role, active := s.profile.ID, s.profile.Role, s.profile.Active
+		merge(&s.profile, &updates)
+		s.profile.ID, s.profile.Role, s.profile.Active = id, role, active
+		writeData(w, http.StatusOK, s.profile)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+func (s *Server) handleAdmin(w http.ResponseWriter, r *http.Request, parts []string, body []byte) {
+	switch {
+	case len(parts) == 1 && parts[0] == "stats" && r.Method == http.MethodGet:
+		s.mu.Lock()
+		stats := atoship.Stats{
+			TotalOrders:    len(s.orders),
+			TotalShipments: len(s.labels),
+			ActiveUsers:    1,
+		}
+		for _, l := range s.labels {
+			stats.TotalRevenue += l.Rate
+		}
+		s.mu.Unlock()
+		writeData(w, http.StatusOK, stats)
+	case len(parts) >= 1 && parts[0] == "webhooks":
+		s.handleWebhooks(w, r, parts[1:], body)
+	default:
+		writeError(w, http.StatusNotFound, atoship.ErrCodeNotFound, "unknown endpoint")
+	}
+}
+
+func (s *Server) handleWebhooks(w http.ResponseWriter, r *http.Request, parts []string, body []byte) {
+	switch {
+	case len(parts) == 0 && r.Method == http.MethodGet:
+		s.mu.Lock()
+		items := make([]atoship.Webhook, len(s.webhooks))
+		for i, wh := range s.webhooks {
+			items[i] = *wh
+		}
+		s.mu.Unlock()
+		writePage(w, r, items)
+	case len(parts) == 0 && r.Method == http.MethodPost:
+		var req struct {
+			URL    string   `json:"url"`
+			Events []string `json:"events"`
+			Active *bool    `json:"active"`
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		if req.URL == "" {
+			writeValidation(w, map[string]string{"url": "is required"})
+			return
+		}
+		s.mu.Lock()
+		wh := &atoship.Webhook{
+			ID:     s.nextID("wh"),
+			URL:    req.URL,
+			Events: req.Events,
+			Active: req.Active == nil || *req.Active,
+		}
+		wh.Secret = fmt.Sprintf("whsec_%s_%d", wh.ID, time.Now().UnixNano())
+		s.webhooks = append(s.webhooks, wh)
+		s.mu.Unlock()
+		writeData(w, http.StatusCreated, wh)
+	case len(parts) == 1:
+		s.handleWebhook(w, r, parts[0], body)
+	case len(parts) == 2 && parts[1] == "rotate-secret" && r.Method == http.MethodPost:
+		s.mu.Lock()
+		wh := s.findWebhook(parts[0])
+		if wh == nil {
+			s.mu.Unlock()
+			notFound(w, "webhook", parts[0])
+			return
+		}
+		wh.Secret = fmt.Sprintf("whsec_%s_%d", wh.ID, time.Now().UnixNano())
+		out := *wh
+		s.mu.Unlock()
+		writeData(w, http.StatusOK, out)
+	case len(parts) == 2 && parts[1] == "test" && r.Method == http.MethodPost:
+		var req struct {
+			EventType string `json:"eventType"`
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		if req.EventType == "" {
+			req.EventType = atoship.EventOrderCreated
+		}
+		s.mu.Lock()
+		wh := s.findWebhook(parts[0])
+		if wh == nil {
+			s.mu.Unlock()
+			notFound(w, "webhook", parts[0])
+			return
+		}
+		target := *wh
+		eventID := s.nextID("evt")
+		s.mu.Unlock()
+		payload, err := sampleEvent(eventID, req.EventType)
+		if err != nil {
+			writeValidation(w, map[string]string{"eventType": err.Error()})
+			return
+		}
+		writeData(w, http.StatusOK, s.deliver(r.Context(), &target, eventID, req.EventType, payload, 1))
+	case len(parts) == 2 && parts[1] == "deliveries" && r.Method == http.MethodGet:
+		s.mu.Lock()
+		if s.findWebhook(parts[0]) == nil {
+			s.mu.Unlock()
+			notFound(w, "webhook", parts[0])
+			return
+		}
+		var items []atoship.WebhookDelivery
+		for i := len(s.delivery) - 1; i >= 0; i-- {
+			if s.delivery[i].WebhookID == parts[0] {
+				items = append(items, *s.delivery[i])
+			}
+		}
+		s.mu.Unlock()
+		writePage(w, r, items)
+	case len(parts) == 4 && parts[1] == "deliveries" && parts[3] == "retry" && r.Method == http.MethodPost:
+		s.mu.Lock()
+		wh := s.findWebhook(parts[0])
+		var prev *atoship.WebhookDelivery
+		for _, d := range s.delivery {
+			if d.ID == parts[2] && d.WebhookID == parts[0] {
+				prev = d
+			}
+		}
+		if wh == nil || prev == nil {
+			s.mu.Unlock()
+			notFound(w, "delivery", parts[2])
+			return
+		}
+		target, retried, payload := *wh, *prev, s.payloads[prev.ID]
+		attempt := 1
+		for _, d := range s.delivery {
+			if d.EventID == retried.EventID && d.Attempt >= attempt {
+				attempt = d.Attempt + 1
+			}
+		}
+		s.mu.Unlock()
+		writeData(w, http.StatusOK, s.deliver(r.Context(), &target, retried.EventID, retried.EventType, payload, attempt))
+	default:
+		writeError(w, http.StatusNotFound, atoship.ErrCodeNotFound, "unknown endpoint")
+	}
+}
+
+func (s *Server) handleWebhook(w http.ResponseWriter, r *http.Request, id string, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wh := s.findWebhook(id)
+	if wh == nil {
+		notFound(w, "webhook", id)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeData(w, http.StatusOK, wh)
+	case http.MethodPatch:
+		var req struct {
+			URL    string   `json:"url"`
+			Events []string `json:"events"`
+			Active *bool    `json:"active"`
+		}
+		if !decode(w, body, &req) {
+			return
+		}
+		if req.URL != "" {
+			wh.URL = req.URL
+		}
+		if req.Events != nil {
+			wh.Events = req.Events
+		}
+		if req.Active != nil {
+			wh.Active = *req.Active
+		}
+		writeData(w, http.StatusOK, wh)
+	case http.MethodDelete:
+		for i, other := range s.webhooks {
+			if other.ID == id {
+				s.webhooks = append(s.webhooks[:i], s.webhooks[i+1:]...)
+				break
+			}
+		}
+		writeData(w, http.StatusOK, nil)
+	default:
+		methodNotAllowed(w)
+	}
+}
+
+// findWebhook returns the webhook with the given ID. The caller must hold
+// s.mu.
+func (s *Server) findWebhook(id string) *atoship.Webhook {
+	for _, wh := range s.webhooks {
+		if wh.ID == id {
+			return wh
+		}
+	}
+	return nil
+}
@@ -0,0 +1,591 @@
+// Package atoshiptest provides an in-process fake of the atoship API for
+// testing code that uses the SDK without network access.
+//
+//	client, srv := atoshiptest.NewClient()
+//	defer srv.Close()
+//
+//	order, err := client.Orders.Create(ctx, &atoship.CreateOrderRequest{...})
+//
+// The fake keeps orders, addresses, labels, tracking and webhooks in memory,
+// wraps every response in the standard APIResponse envelope and can be told
+// to fail or slow down specific endpoints.
+//
+// For tests against the real API, Recorder captures request/response pairs
+// to a cassette file once and replays them deterministically afterwards.
+package atoshiptest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+// DefaultAPIKey is the API key accepted by a new Server
+const DefaultAPIKey = "test_atoshiptest_key"
+
+// Failure describes an error response injected by the Server
+type Failure struct {
+	// Method restricts the failure to one HTTP method; empty matches any
+	Method string
+	// Path restricts the failure to paths with this prefix; empty matches any
+	Path string
+	// Status is the HTTP status to respond with; defaults to 500
+	Status int
+	// Code is the error code in the response body; defaults to one matching
+	// Status
+	Code string
+	// Message is the error message in the response body
+	Message string
+	// RetryAfter, when set, is sent as a Retry-After header
+	RetryAfter time.Duration
+	// Drop closes the connection without responding, simulating a network
+	// error. Status, Code and Message are ignored.
+	Drop bool
+	// Times is the number of matching requests to fail; zero fails every
+	// matching request until ClearFailures is called
+	Times int
+}
+
+// Request records a request received by the Server
+type Request struct {
+	Method string
+	Path   string
+	Query  string
+	Header http.Header
+	// Body is the request body, decompressed if it was gzipped
+	Body []byte
+	// BodySize is the size of the body as received, before decompression
+	BodySize int
+}
+
+// Server is an in-memory fake of the atoship API
+type Server struct {
+	*httptest.Server
+
+	// APIKey is the key clients must send in X-API-Key
+	APIKey string
+
+	mu        sync.Mutex
+	seq       int
+	latency   time.Duration
+	failures  []*Failure
+	requests  []Request
+	idem      map[string]*recordedResponse
+	orders    []*atoship.Order
+	addresses []*atoship.Address
+	rates     map[string]*atoship.ShippingRate
+	labels    map[string]*atoship.ShippingLabel
+	tracking  map[string]*atoship.TrackingInfo
+	webhooks  []*atoship.Webhook
+	delivery  []*atoship.WebhookDelivery
+	payloads  map[string][]byte
+	carriers  []atoship.Carrier
+	profile   atoship.User
+}
+
+// recordedResponse is a response replayed for a repeated idempotency key
+type recordedResponse struct {
+	// done is closed once the request holding the key has finished; status
+	// is zero if it left nothing to replay
+	done   chan struct{}
+	status int
+	body   []byte
+}
+
+// NewServer starts a fake atoship API server. Callers must Close it when
+// done.
+func NewServer() *Server {
+	s := &Server{
+		APIKey:   DefaultAPIKey,
+		idem:     make(map[string]*recordedResponse),
+		rates:    make(map[string]*atoship.ShippingRate),
+		labels:   make(map[string]*atoship.ShippingLabel),
+		tracking: make(map[string]*atoship.TrackingInfo),
+		payloads: make(map[string][]byte),
+		carriers: []atoship.Carrier{
+			{ID: "car_usps", Name: "USPS", Code: "usps", Active: true, Services: []string{"Priority", "Ground Advantage"}},
+			{ID: "car_ups", Name: "UPS", Code: "ups", Active: true, Services: []string{"Ground", "2nd Day Air"}},
+			{ID: "car_fedex", Name: "FedEx", Code: "fedex", Active: true, Services: []string{"Ground", "Express Saver"}},
+		},
+		profile: atoship.User{
+			ID:     "usr_1",
+			Email:  "developer@example.com",
+			Name:   "Test Developer",
+			Role:   "admin",
+			Active: true,
+		},
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// NewClient starts a fake server and returns a client wired to it. Callers
+// must Close the server when done.
+func NewClient(opts ...atoship.ClientOption) (*atoship.Client, *Server) {
+	s := NewServer()
+	return s.Client(opts...), s
+}
+
+// Client returns a client configured to talk to the server
+func (s *Server) Client(opts ...atoship.ClientOption) *atoship.Client {
+	opts = append([]atoship.ClientOption{atoship.WithBaseURL(s.URL)}, opts...)
+	return atoship.NewClient(s.APIKey, opts...)
+}
+
+// SetLatency delays every response by d
+func (s *Server) SetLatency(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+}
+
+// InjectFailure makes matching requests fail. Failures are checked in the
+// order they were injected.
+func (s *Server) InjectFailure(f Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = append(s.failures, &f)
+}
+
+// ClearFailures removes every injected failure
+func (s *Server) ClearFailures() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failures = nil
+}
+
+// Requests returns every request received so far
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Orders returns a snapshot of the stored orders
+func (s *Server) Orders() []atoship.Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]atoship.Order, len(s.orders))
+	for i, o := range s.orders {
+		out[i] = *o
+	}
+	return out
+}
+
+// Labels returns a snapshot of the purchased labels
+func (s *Server) Labels() []atoship.ShippingLabel {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]atoship.ShippingLabel, 0, len(s.labels))
+	for _, l := range s.labels {
+		out = append(out, *l)
+	}
+	return out
+}
+
+// Webhooks returns a snapshot of the configured webhooks
+func (s *Server) Webhooks() []atoship.Webhook {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]atoship.Webhook, len(s.webhooks))
+	for i, w := range s.webhooks {
+		out[i] = *w
+	}
+	return out
+}
+
+// Deliveries returns a snapshot of the webhook deliveries made by the
+// server, oldest first
+func (s *Server) Deliveries() []atoship.WebhookDelivery {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	out := make([]atoship.WebhookDelivery, len(s.delivery))
+	for i, d := range s.delivery {
+		out[i] = *d
+	}
+	return out
+}
+
+// SetTracking stores tracking information returned by the tracking
+// endpoints
+func (s *Server) SetTracking(info atoship.TrackingInfo) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tracking[info.TrackingNumber] = &info
+}
+
+// nextID returns a new identifier with the given prefix. The caller must
+// hold s.mu.
+func (s *Server) nextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s_%d", prefix, s.seq)
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := readBody(r)
+	size := len(body)
+	if strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+		if plain, err := gunzip(body); err == nil {
+			body = plain
+		}
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{
+		Method:   r.Method,
+		Path:     r.URL.Path,
+		Query:    r.URL.RawQuery,
+		Header:   r.Header.Clone(),
+		Body:     body,
+		BodySize: size,
+	})
+	latency := s.latency
+	failure := s.matchFailure(r)
+	s.mu.Unlock()
+
+	// Claim a repeated idempotency key before the simulated processing
+	// time, so that concurrent duplicates wait for the first request and
+	// replay its response
+	var claimed, prev *recordedResponse
+	key := r.Header.Get("Idempotency-Key")
+	if failure == nil && key != "" && r.Method == http.MethodPost && r.Header.Get("X-API-Key") == s.APIKey {
+		key = r.URL.Path + "\x00" + key
+		claimed, prev = s.claimIdempotencyKey(r.Context(), key)
+		if claimed == nil && prev == nil {
+			return
+		}
+	}
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			if claimed != nil {
+				s.releaseIdempotencyKey(key, claimed, nil)
+			}
+			return
+		}
+	}
+
+	if failure != nil {
+		writeFailure(w, failure)
+		return
+	}
+
+	if strings.HasPrefix(r.URL.Path, labelFilePrefix) {
+		s.serveLabelFile(w, r)
+		return
+	}
+
+	if r.Header.Get("X-API-Key") != s.APIKey {
+		writeError(w, http.StatusUnauthorized, atoship.ErrCodeAuthentication, "invalid API key")
+		return
+	}
+
+	// Replay the stored response for a repeated idempotency key
+	if prev != nil {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Idempotent-Replayed", "true")
+		w.WriteHeader(prev.status)
+		w.Write(prev.body)
+		return
+	}
+
+	rec := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.route(rec, r, body)
+	if claimed != nil {
+		s.releaseIdempotencyKey(key, claimed, rec)
+	}
+}
+
+// claimIdempotencyKey reserves key for a request, so that concurrent
+// requests with the same key run one at a time. If another request holds
+// the key, it waits for that request and returns its response to replay.
+// It returns nil, nil if ctx is done first.
+func (s *Server) claimIdempotencyKey(ctx context.Context, key string) (claimed, replay *recordedResponse) {
+	for {
+		s.mu.Lock()
+		prev := s.idem[key]
+		if prev == nil {
+			claimed = &recordedResponse{done: make(chan struct{})}
+			s.idem[key] = claimed
+			s.mu.Unlock()
+			return claimed, nil
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-prev.done:
+		case <-ctx.Done():
+			return nil, nil
+		}
+		if prev.status != 0 {
+			return nil, prev
+		}
+		// The request holding the key failed without a response worth
+		// replaying, so claim the key again
+	}
+}
+
+// releaseIdempotencyKey stores the response of the request that claimed
+// key for replay, or frees the key if rec is nil or a server error
+func (s *Server) releaseIdempotencyKey(key string, claimed *recordedResponse, rec *responseRecorder) {
+	s.mu.Lock()
+	if rec != nil && rec.status < http.StatusInternalServerError {
+		claimed.status, claimed.body = rec.status, rec.body
+	} else {
+		delete(s.idem, key)
+	}
+	s.mu.Unlock()
+	close(claimed.done)
+}
+
+// matchFailure returns the first injected failure matching r. The caller
+// must hold s.mu.
+func (s *Server) matchFailure(r *http.Request) *Failure {
+	for i, f := range s.failures {
+		if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+			continue
+		}
+		if f.Path != "" && !strings.HasPrefix(r.URL.Path, f.Path) {
+			continue
+		}
+		matched := *f
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.failures = append(s.failures[:i:i], s.failures[i+1:]...)
+			}
+		}
+		return &matched
+	}
+	return nil
+}
+
+// route dispatches a request to the handler for its endpoint
+func (s *Server) route(w http.ResponseWriter, r *http.Request, body []byte) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api"), "/"), "/")
+	if len(parts) == 0 || parts[0] == "" {
+		writeError(w, http.StatusNotFound, atoship.ErrCodeNotFound, "unknown endpoint")
+		return
+	}
+
+	switch parts[0] {
+	case "orders":
+		s.handleOrders(w, r, parts[1:], body)
+	case "addresses":
+		s.handleAddresses(w, r, parts[1:], body)
+	case "address-search":
+		s.handleAddressSearch(w, r)
+	case "carriers":
+		s.handleCarriers(w, r, parts[1:], body)
+	case "labels":
+		s.handleLabels(w, r, parts[1:], body)
+	case "tracking":
+		s.handleTracking(w, r, parts[1:], body)
+	case "profile":
+		s.handleProfile(w, r, body)
+	case "admin":
+		s.handleAdmin(w, r, parts[1:], body)
+	default:
+		writeError(w, http.StatusNotFound, atoship.ErrCodeNotFound, "unknown endpoint")
+	}
+}
+
+// responseRecorder captures a response so it can be replayed
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   []byte
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body = append(r.body, b...)
+	return r.ResponseWriter.Write(b)
+}
+
+var requestSeq struct {
+	sync.Mutex
+	n int
+}
+
+// newRequestID returns a unique request identifier
+func newRequestID() string {
+	requestSeq.Lock()
+	defer requestSeq.Unlock()
+	requestSeq.n++
+	return "req_" + strconv.Itoa(requestSeq.n)
+}
+
+// writeData writes a successful APIResponse envelope
+func writeData(w http.ResponseWriter, status int, data any) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, atoship.ErrCodeServerError, err.Error())
+		return
+	}
+	writeJSON(w, status, atoship.APIResponse{
+		Success:   true,
+		Data:      raw,
+		RequestID: newRequestID(),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// writeError writes a failed APIResponse envelope
+func writeError(w http.ResponseWriter, status int, code, message string) {
+	writeJSON(w, status, map[string]any{
+		"success":   false,
+		"code":      code,
+		"message":   message,
+		"error":     message,
+		"requestId": newRequestID(),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// writeValidation writes a validation error with field-level details
+func writeValidation(w http.ResponseWriter, fields map[string]string) {
+	writeJSON(w, http.StatusUnprocessableEntity, map[string]any{
+		"success":   false,
+		"code":      atoship.ErrCodeValidation,
+		"message":   "validation failed",
+		"error":     "validation failed",
+		"details":   fields,
+		"requestId": newRequestID(),
+		"timestamp": time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if m, ok := v.(atoship.APIResponse); ok {
+		w.Header().Set("X-Request-Id", m.RequestID)
+	} else if m, ok := v.(map[string]any); ok {
+		w.Header().Set("X-Request-Id", fmt.Sprint(m["requestId"]))
+	}
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeFailure writes an injected failure
+func writeFailure(w http.ResponseWriter, f *Failure) {
+	if f.Drop {
+		if hj, ok := w.(http.Hijacker); ok {
+			if conn, _, err := hj.Hijack(); err == nil {
+				conn.Close()
+				return
+			}
+		}
+	}
+
+	status := f.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	code := f.Code
+	if code == "" {
+		code = codeForStatus(status)
+	}
+	message := f.Message
+	if message == "" {
+		message = http.StatusText(status)
+	}
+	if f.RetryAfter > 0 {
+		secs := int((f.RetryAfter + time.Second - 1) / time.Second)
+		w.Header().Set("Retry-After", strconv.Itoa(secs))
+	}
+	writeError(w, status, code, message)
+}
+
+// codeForStatus maps an HTTP status to an atoship error code
+func codeForStatus(status int) string {
+	switch {
+	case status == http.StatusBadRequest, status == http.StatusUnprocessableEntity:
+		return atoship.ErrCodeValidation
+	case status == http.StatusUnauthorized:
+		return atoship.ErrCodeAuthentication
+	case status == http.StatusForbidden:
+		return atoship.ErrCodeAuthorization
+	case status == http.StatusNotFound:
+		return atoship.ErrCodeNotFound
+	case status == http.StatusTooManyRequests:
+		return atoship.ErrCodeRateLimit
+	}
+	return atoship.ErrCodeServerError
+}
+
+// methodNotAllowed writes a 405 response
+func methodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, atoship.ErrCodeAPIError, "method not allowed")
+}
+
+// notFound writes a 404 response for the named resource
+func notFound(w http.ResponseWriter, resource, id string) {
+	writeError(w, http.StatusNotFound, atoship.ErrCodeNotFound, fmt.Sprintf("%s %s not found", resource, id))
+}
+
+// decode unmarshals a JSON request body, writing a 400 response on failure
+func decode(w http.ResponseWriter, body []byte, v any) bool {
+	if len(body) == 0 {
+		return true
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		writeError(w, http.StatusBadRequest, atoship.ErrCodeValidation, "invalid JSON body: "+err.Error())
+		return false
+	}
+	return true
+}
+
+// paging returns the page and limit query parameters
+func paging(r *http.Request, defaultLimit int) (page, limit int) {
+	page, _ = strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit, _ = strconv.Atoi(r.URL.Query().Get("limit"))
+	if limit < 1 {
+		limit = defaultLimit
+	}
+	return page, limit
+}
+
+// pageBounds returns the slice bounds of a page within total items
+func pageBounds(total, page, limit int) (start, end int, hasMore bool) {
+	start = (page - 1) * limit
+	if start > total {
+		start = total
+	}
+	end = start + limit
+	if end > total {
+		end = total
+	}
+	return start, end, end < total
+}
+
+// writePage writes a PaginatedResponse
+func writePage[T any](w http.ResponseWriter, r *http.Request, items []T) {
+	page, limit := paging(r, 20)
+	start, end, hasMore := pageBounds(len(items), page, limit)
+	raw, _ := json.Marshal(items[start:end])
+	writeData(w, http.StatusOK, atoship.PaginatedResponse{
+		Items:   raw,
+		Total:   len(items),
+		Page:    page,
+		Limit:   limit,
+		HasMore: hasMore,
+	})
+}
@@ -0,0 +1,352 @@
+package atoshiptest_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+	"github.com/atoship-LLC/atoship-go/atoship/atoshiptest"
+)
+
+func newOrder(n int) *atoship.CreateOrderRequest {
+	return &atoship.CreateOrderRequest{
+		OrderNumber:      fmt.Sprintf("ORD-%04d", n),
+		RecipientName:    "Jane Doe",
+		RecipientStreet1: "123 Main St",
+		RecipientCity:    "Austin",
+		RecipientState:   "TX",
+		RecipientPostal:  "78701",
+		RecipientCountry: "US",
+		Items:            []atoship.OrderItem{{Name: "Widget", SKU: "WID-1", Quantity: 1, UnitPrice: 9.99}},
+	}
+}
+
+func TestOrdersCRUD(t *testing.T) {
+	ctx := context.Background()
+	client, srv := atoshiptest.NewClient()
+	defer srv.Close()
+
+	created, err := client.Orders.Create(ctx, newOrder(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID == "" || created.OrderNumber != "ORD-0001" {
+		t.Fatalf("created order = %+v", created)
+	}
+
+	got, err := client.Orders.Get(ctx, created.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.RecipientName != "Jane Doe" {
+		t.Errorf("RecipientName = %q", got.RecipientName)
+	}
+
+	updated, err := client.Orders.Update(ctx, created.ID, &atoship.UpdateOrderRequest{RecipientCity: "Dallas"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.RecipientCity != "Dallas" || updated.RecipientName != "Jane Doe" {
+		t.Errorf("updated order = %+v", updated)
+	}
+
+	cancelled, err := client.Orders.Cancel(ctx, created.ID, "customer request")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cancelled.Status != "cancelled" {
+		t.Errorf("Status = %q, want cancelled", cancelled.Status)
+	}
+
+	if err := client.Orders.Delete(ctx, created.ID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Orders.Get(ctx, created.ID); !errors.Is(err, atoship.ErrNotFound) {
+		t.Errorf("Get after Delete: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestOrderValidation(t *testing.T) {
+	client, srv := atoshiptest.NewClient()
+	defer srv.Close()
+
+	req := newOrder(1)
+	req.RecipientCity = ""
+	_, err := client.Orders.Create(context.Background(), req)
+	if !errors.Is(err, atoship.ErrValidation) {
+		t.Fatalf("err = %v, want ErrValidation", err)
+	}
+	var apiErr *atoship.APIError
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("err = %T, want *APIError", err)
+	}
+	fields := apiErr.FieldErrors()
+	if len(fields) != 1 || fields[0].Field != "recipientCity" {
+		t.Errorf("FieldErrors = %+v, want recipientCity", fields)
+	}
+}
+
+func TestOrdersPaging(t *testing.T) {
+	ctx := context.Background()
+	client, srv := atoshiptest.NewClient()
+	defer srv.Close()
+
+	for i := 1; i <= 25; i++ {
+		if _, err := client.Orders.Create(ctx, newOrder(i)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	list, err := client.Orders.List(ctx, &atoship.ListOrdersOptions{Page: 3, Limit: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Orders) != 5 || list.Total != 25 || list.HasMore {
+		t.Errorf("page 3 = %d orders, total %d, hasMore %v; want 5, 25, false", len(list.Orders), list.Total, list.HasMore)
+	}
+
+	all, err := client.Orders.ListPager(&atoship.ListOrdersOptions{Limit: 10}).All(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 25 {
+		t.Errorf("pager returned %d orders, want 25", len(all))
+	}
+
+	seen := map[string]bool{}
+	err = client.Orders.ListAll(ctx, &atoship.ListOrdersOptions{Limit: 7}, func(o *atoship.Order) error {
+		seen[o.ID] = true
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 25 {
+		t.Errorf("ListAll visited %d distinct orders, want 25", len(seen))
+	}
+
+	pages := 0
+	for _, r := range srv.Requests() {
+		if r.Method == http.MethodGet && r.Path == "/api/orders" {
+			pages++
+		}
+	}
+	// One List call, three pager pages and four ListAll pages
+	if pages != 8 {
+		t.Errorf("server saw %d list requests, want 8", pages)
+	}
+}
+
+func TestPurchaseLabel(t *testing.T) {
+	ctx := context.Background()
+	client, srv := atoshiptest.NewClient()
+	defer srv.Close()
+
+	order, err := client.Orders.Create(ctx, newOrder(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rates, err := client.Shipping.GetRates(ctx, &atoship.RateRequest{
+		FromAddress: &atoship.Address{Street1: "1 Depot Rd", City: "Dallas", State: "TX", PostalCode: "75201", Country: "US"},
+		ToAddress:   &atoship.Address{Street1: "123 Main St", City: "Austin", State: "TX", PostalCode: "78701", Country: "US"},
+		Parcel:      &atoship.Parcel{Weight: 16, WeightUnit: "oz"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) == 0 {
+		t.Fatal("no rates")
+	}
+
+	label, err := client.Shipping.PurchaseLabel(ctx, &atoship.PurchaseLabelRequest{RateID: rates[0].ID, OrderID: order.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label.TrackingNumber == "" || label.Carrier != rates[0].Carrier {
+		t.Errorf("label = %+v", label)
+	}
+
+	resp, err := http.Get(label.LabelURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pdf, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !bytes.HasPrefix(pdf, []byte("%PDF-")) || !bytes.Contains(pdf, []byte(label.TrackingNumber)) {
+		t.Errorf("GET %s = %s, %q; want the label PDF", label.LabelURL, resp.Status, pdf)
+	}
+	resp, err = http.Get(srv.URL + "/labels/lbl_missing.pdf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("missing label PDF: %s, want 404", resp.Status)
+	}
+
+	order, err = client.Orders.Get(ctx, order.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.TrackingNumber != label.TrackingNumber {
+		t.Errorf("order tracking number = %q, want %q", order.TrackingNumber, label.TrackingNumber)
+	}
+
+	info, err := client.Tracking.Track(ctx, label.TrackingNumber)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Status != "PRE_TRANSIT" {
+		t.Errorf("tracking status = %q, want PRE_TRANSIT", info.Status)
+	}
+
+	_, err = client.Shipping.PurchaseLabel(ctx, &atoship.PurchaseLabelRequest{RateID: "rate_unknown"})
+	if !errors.Is(err, atoship.ErrValidation) {
+		t.Errorf("unknown rate: err = %v, want ErrValidation", err)
+	}
+}
+
+func TestIdempotentReplay(t *testing.T) {
+	client, srv := atoshiptest.NewClient()
+	defer srv.Close()
+
+	ctx := atoship.WithIdempotencyKey(context.Background(), "order-1")
+	first, err := client.Orders.Create(ctx, newOrder(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var meta atoship.ResponseMeta
+	second, err := client.Orders.Create(atoship.WithResponseMeta(ctx, &meta), newOrder(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.ID != first.ID {
+		t.Errorf("replayed order ID = %q, want %q", second.ID, first.ID)
+	}
+	if meta.Header.Get("Idempotent-Replayed") != "true" {
+		t.Error("second response was not marked as replayed")
+	}
+	if n := len(srv.Orders()); n != 1 {
+		t.Errorf("server has %d orders, want 1", n)
+	}
+}
+
+func TestIdempotentConcurrentDuplicates(t *testing.T) {
+	client, srv := atoshiptest.NewClient()
+	defer srv.Close()
+	// Hold every request so the duplicates reach the handler together
+	srv.SetLatency(20 * time.Millisecond)
+
+	ctx := atoship.WithIdempotencyKey(context.Background(), "order-1")
+	const n = 20
+	ids := make([]string, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			order, err := client.Orders.Create(ctx, newOrder(1))
+			if err == nil {
+				ids[i] = order.ID
+			}
+			errs[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range ids {
+		if errs[i] != nil {
+			t.Fatalf("request %d: %v", i, errs[i])
+		}
+		if ids[i] != ids[0] {
+			t.Errorf("request %d created %q, want the replayed %q", i, ids[i], ids[0])
+		}
+	}
+	if got := len(srv.Orders()); got != 1 {
+		t.Errorf("server has %d orders, want 1", got)
+	}
+}
+
+func TestInjectedFailuresAreRetried(t *testing.T) {
+	client, srv := atoshiptest.NewClient(atoship.WithRetryPolicy(atoship.RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: time.Millisecond,
+	}))
+	defer srv.Close()
+
+	srv.InjectFailure(atoshiptest.Failure{Method: http.MethodPost, Path: "/api/orders", Status: http.StatusServiceUnavailable, Times: 2})
+	var meta atoship.ResponseMeta
+	order, err := client.Orders.Create(atoship.WithResponseMeta(context.Background(), &meta), newOrder(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", meta.Attempts)
+	}
+	if n := len(srv.Orders()); n != 1 || srv.Orders()[0].ID != order.ID {
+		t.Errorf("server has %d orders, want the one created", n)
+	}
+}
+
+func TestInjectedFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		failure atoshiptest.Failure
+		want    error
+	}{
+		{"server error", atoshiptest.Failure{Status: http.StatusInternalServerError}, atoship.ErrServer},
+		{"rate limited", atoshiptest.Failure{Status: http.StatusTooManyRequests, RetryAfter: time.Second}, atoship.ErrRateLimited},
+		{"not found", atoshiptest.Failure{Status: http.StatusNotFound}, atoship.ErrNotFound},
+		{"dropped connection", atoshiptest.Failure{Drop: true}, atoship.ErrNetwork},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, srv := atoshiptest.NewClient()
+			defer srv.Close()
+
+			srv.InjectFailure(tt.failure)
+			_, err := client.Carriers.List(context.Background())
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+
+			srv.ClearFailures()
+			if _, err := client.Carriers.List(context.Background()); err != nil {
+				t.Errorf("after ClearFailures: %v", err)
+			}
+		})
+	}
+}
+
+func TestLatency(t *testing.T) {
+	client, srv := atoshiptest.NewClient(atoship.WithTimeout(20 * time.Millisecond))
+	defer srv.Close()
+
+	srv.SetLatency(200 * time.Millisecond)
+	if _, err := client.Users.GetProfile(context.Background()); !errors.Is(err, atoship.ErrTimeout) {
+		t.Errorf("err = %v, want ErrTimeout", err)
+	}
+
+	srv.SetLatency(0)
+	if _, err := client.Users.GetProfile(context.Background()); err != nil {
+		t.Errorf("without latency: %v", err)
+	}
+}
+
+func TestWrongAPIKey(t *testing.T) {
+	srv := atoshiptest.NewServer()
+	defer srv.Close()
+
+	client := atoship.NewClient("test_wrong", atoship.WithBaseURL(srv.URL))
+	if _, err := client.Users.GetProfile(context.Background()); !errors.Is(err, atoship.ErrUnauthorized) {
+		t.Errorf("err = %v, want ErrUnauthorized", err)
+	}
+}
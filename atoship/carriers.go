@@ -16,9 +16,12 @@ type Carrier struct {
 	Services   []string `json:"services"`
 }
 
-// List lists all available carriers
+// List lists all available carriers, following pagination until every page is read
 func (s *CarriersService) List(ctx context.Context) ([]Carrier, error) {
-	var carriers []Carrier
-	err := s.client.get(ctx, "/api/carriers", &carriers)
-	return carriers, err
+	return s.ListPager(nil).All(ctx)
+}
+
+// ListPager returns a Pager over the available carriers
+func (s *CarriersService) ListPager(opts *ListOptions) *Pager[Carrier] {
+	return newListPager[Carrier](s.client, "/api/carriers", opts)
 }
\ No newline at end of file
@@ -0,0 +1,314 @@
+package atoship
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCodeCircuitOpen is the code of the error returned when a circuit
+// breaker rejects a call
+const ErrCodeCircuitOpen = "CIRCUIT_OPEN"
+
+// ErrCircuitOpen is wrapped by the error returned when a circuit breaker
+// rejects a call without sending it
+var ErrCircuitOpen = errors.New("atoship: circuit breaker open")
+
+// CircuitState is the state of a circuit breaker
+type CircuitState int
+
+// Circuit breaker states
+const (
+	// CircuitClosed lets every call through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen rejects every call with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen lets a limited number of probe calls through
+	CircuitHalfOpen
+)
+
+// String returns the state name
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// CircuitBreakerSettings controls when a circuit breaker trips and recovers.
+//
+// Network errors, timeouts and 5xx responses count as failures, after any
+// retries, as do calls that fail once their context deadline has passed.
+// Other errors, such as validation failures, show that the API is up and
+// count as successes; calls canceled by the caller are not counted.
+type CircuitBreakerSettings struct {
+	// FailureRatio trips the breaker when at least this fraction of the
+	// calls in a window fail (0 to 1)
+	FailureRatio float64
+	// MinRequests is the number of calls a window needs before the
+	// breaker can trip
+	MinRequests int
+	// Window is the period over which calls are counted
+	Window time.Duration
+	// OpenTimeout is how long the breaker stays open before letting
+	// probe calls through
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of probe calls let through while half
+	// open. The breaker closes once they all succeed and reopens as soon
+	// as one fails.
+	HalfOpenProbes int
+}
+
+// DefaultCircuitBreakerSettings returns the settings used for zero fields
+// of the settings given to WithCircuitBreaker
+func DefaultCircuitBreakerSettings() CircuitBreakerSettings {
+	return CircuitBreakerSettings{
+		FailureRatio:   0.5,
+		MinRequests:    10,
+		Window:         time.Minute,
+		OpenTimeout:    30 * time.Second,
+		HalfOpenProbes: 1,
+	}
+}
+
+// WithCircuitBreaker gives every service its own circuit breaker, so that,
+// for example, tracking failures do not block label purchases. While a
+// service's breaker is open its calls fail immediately with an error
+// matching ErrCircuitOpen instead of waiting for a timeout. Zero settings
+// fall back to those of DefaultCircuitBreakerSettings.
+func WithCircuitBreaker(settings CircuitBreakerSettings) ClientOption {
+	return func(c *Client) {
+		settings = settings.withDefaults()
+		c.circuitDefault = &settings
+	}
+}
+
+// WithServiceCircuitBreaker sets the circuit breaker settings of one
+// service, named as in the operation names reported to Instrumentation:
+// "orders", "addresses", "shipping", "tracking", "carriers", "users",
+// "admin" or "webhooks". Without WithCircuitBreaker, only the services
+// configured this way have a breaker.
+func WithServiceCircuitBreaker(service string, settings CircuitBreakerSettings) ClientOption {
+	return func(c *Client) {
+		if c.circuitSettings == nil {
+			c.circuitSettings = map[string]CircuitBreakerSettings{}
+		}
+		c.circuitSettings[service] = settings.withDefaults()
+	}
+}
+
+// CircuitState returns the state of a service's circuit breaker. Services
+// without a breaker are always closed.
+func (c *Client) CircuitState(service string) CircuitState {
+	b := c.breakers[service]
+	if b == nil {
+		return CircuitClosed
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.currentState(time.Now())
+}
+
+func (s CircuitBreakerSettings) withDefaults() CircuitBreakerSettings {
+	def := DefaultCircuitBreakerSettings()
+	if s.FailureRatio <= 0 || s.FailureRatio > 1 {
+		s.FailureRatio = def.FailureRatio
+	}
+	if s.MinRequests <= 0 {
+		s.MinRequests = def.MinRequests
+	}
+	if s.Window <= 0 {
+		s.Window = def.Window
+	}
+	if s.OpenTimeout <= 0 {
+		s.OpenTimeout = def.OpenTimeout
+	}
+	if s.HalfOpenProbes <= 0 {
+		s.HalfOpenProbes = def.HalfOpenProbes
+	}
+	return s
+}
+
+// newBreakers creates the circuit breakers configured on c, by service
+func (c *Client) newBreakers() map[string]*circuitBreaker {
+	if c.circuitDefault == nil && len(c.circuitSettings) == 0 {
+		return nil
+	}
+	breakers := map[string]*circuitBreaker{}
+	add := func(service string) {
+		if breakers[service] != nil {
+			return
+		}
+		settings, ok := c.circuitSettings[service]
+		if !ok {
+			if c.circuitDefault == nil {
+				return
+			}
+			settings = *c.circuitDefault
+		}
+		breakers[service] = &circuitBreaker{service: service, settings: settings}
+	}
+	for _, r := range routes {
+		service, _, _ := strings.Cut(r.operation, ".")
+		add(service)
+	}
+	for service := range c.circuitSettings {
+		add(service)
+	}
+	return breakers
+}
+
+// breakerFor returns the circuit breaker guarding an operation, if any
+func (c *Client) breakerFor(operation string) *circuitBreaker {
+	if c.breakers == nil || operation == "" {
+		return nil
+	}
+	service, _, _ := strings.Cut(operation, ".")
+	return c.breakers[service]
+}
+
+// circuitBreaker counts the failures of one service's calls over fixed
+// windows
+type circuitBreaker struct {
+	service  string
+	settings CircuitBreakerSettings
+
+	mu          sync.Mutex
+	state       CircuitState
+	windowStart time.Time
+	calls       int
+	failures    int
+	openedAt    time.Time
+	probes      int
+	successes   int
+}
+
+// currentState moves an open breaker to half open once OpenTimeout has
+// passed. b.mu must be held.
+func (b *circuitBreaker) currentState(now time.Time) CircuitState {
+	if b.state == CircuitOpen && now.Sub(b.openedAt) >= b.settings.OpenTimeout {
+		b.state = CircuitHalfOpen
+		b.probes = 0
+		b.successes = 0
+	}
+	return b.state
+}
+
+// allow reports whether a call may be sent, and whether it is a half-open
+// probe
+func (b *circuitBreaker) allow() (probe bool, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	switch b.currentState(now) {
+	case CircuitOpen:
+		wait := b.settings.OpenTimeout - now.Sub(b.openedAt)
+		return false, b.openError(fmt.Sprintf("retry in %s", wait.Round(time.Millisecond)))
+	case CircuitHalfOpen:
+		if b.probes >= b.settings.HalfOpenProbes {
+			return false, b.openError("probe calls in progress")
+		}
+		b.probes++
+		return true, nil
+	}
+	return false, nil
+}
+
+func (b *circuitBreaker) openError(detail string) error {
+	return &APIError{
+		Code:    ErrCodeCircuitOpen,
+		Message: fmt.Sprintf("circuit breaker for %s is open: %s", b.service, detail),
+		Err:     ErrCircuitOpen,
+	}
+}
+
+// record counts the outcome of a call let through by allow
+func (b *circuitBreaker) record(ctx context.Context, probe bool, err error) {
+	// Calls canceled by the caller say nothing about the API, but a call
+	// that ran out of time was not answered quickly enough
+	neutral := errors.Is(ctx.Err(), context.Canceled)
+	failed := !neutral && err != nil && (isBreakerFailure(err) || ctx.Err() != nil)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+
+	if probe {
+		if b.state != CircuitHalfOpen {
+			return
+		}
+		switch {
+		case failed:
+			b.trip(now)
+		case neutral:
+			b.probes--
+		default:
+			b.successes++
+			if b.successes >= b.settings.HalfOpenProbes {
+				b.state = CircuitClosed
+				b.windowStart = now
+				b.calls = 0
+				b.failures = 0
+			}
+		}
+		return
+	}
+
+	if b.state != CircuitClosed || neutral {
+		return
+	}
+	if now.Sub(b.windowStart) >= b.settings.Window {
+		b.windowStart = now
+		b.calls = 0
+		b.failures = 0
+	}
+	b.calls++
+	if failed {
+		b.failures++
+	}
+	if b.calls >= b.settings.MinRequests && float64(b.failures) >= b.settings.FailureRatio*float64(b.calls) {
+		b.trip(now)
+	}
+}
+
+// release returns the slot of a call let through by allow that was never
+// sent, without counting it
+func (b *circuitBreaker) release(probe bool) {
+	if !probe {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitHalfOpen {
+		b.probes--
+	}
+}
+
+// trip opens the breaker. b.mu must be held.
+func (b *circuitBreaker) trip(now time.Time) {
+	b.state = CircuitOpen
+	b.openedAt = now
+}
+
+// isBreakerFailure reports whether err suggests the API is unavailable
+func isBreakerFailure(err error) bool {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Code {
+	case ErrCodeNetworkError, ErrCodeTimeoutError:
+		return true
+	}
+	return apiErr.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,245 @@
+package atoship
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// breakerServer answers GET /api/carriers with the status it is given,
+// after an optional delay
+type breakerServer struct {
+	*httptest.Server
+	status atomic.Int32
+	delay  atomic.Int64
+	hits   atomic.Int32
+}
+
+func newBreakerServer(t *testing.T) *breakerServer {
+	s := &breakerServer{}
+	s.status.Store(http.StatusOK)
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.hits.Add(1)
+		if d := time.Duration(s.delay.Load()); d > 0 {
+			select {
+			case <-time.After(d):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		status := int(s.status.Load())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status < 300 {
+			fmt.Fprint(w, `{"success":true,"data":[]}`)
+		} else {
+			fmt.Fprintf(w, `{"success":false,"error":"status %d"}`, status)
+		}
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func newBreakerClient(url string, settings CircuitBreakerSettings) *Client {
+	return NewClient("test_key", WithBaseURL(url), WithRetryPolicy(RetryPolicy{}), WithCircuitBreaker(settings))
+}
+
+var testBreakerSettings = CircuitBreakerSettings{
+	FailureRatio: 0.5,
+	MinRequests:  4,
+	Window:       time.Minute,
+	OpenTimeout:  50 * time.Millisecond,
+}
+
+func TestCircuitBreakerTrips(t *testing.T) {
+	srv := newBreakerServer(t)
+	client := newBreakerClient(srv.URL, testBreakerSettings)
+	ctx := context.Background()
+
+	// One failure in three calls stays below MinRequests
+	client.Carriers.List(ctx)
+	client.Carriers.List(ctx)
+	srv.status.Store(http.StatusServiceUnavailable)
+	client.Carriers.List(ctx)
+	if got := client.CircuitState("carriers"); got != CircuitClosed {
+		t.Fatalf("state after 3 calls = %s, want closed", got)
+	}
+
+	// The fourth call makes two failures in four
+	client.Carriers.List(ctx)
+	if got := client.CircuitState("carriers"); got != CircuitOpen {
+		t.Fatalf("state after 4 calls = %s, want open", got)
+	}
+
+	hits := srv.hits.Load()
+	_, err := client.Carriers.List(ctx)
+	if !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("err = %v, want ErrCircuitOpen", err)
+	}
+	if srv.hits.Load() != hits {
+		t.Error("open breaker sent a request")
+	}
+
+	// Other services keep their own breaker
+	if got := client.CircuitState("orders"); got != CircuitClosed {
+		t.Errorf("orders state = %s, want closed", got)
+	}
+}
+
+func TestCircuitBreakerClientErrorsAreSuccesses(t *testing.T) {
+	srv := newBreakerServer(t)
+	srv.status.Store(http.StatusUnprocessableEntity)
+	client := newBreakerClient(srv.URL, testBreakerSettings)
+
+	for i := 0; i < 10; i++ {
+		client.Carriers.List(context.Background())
+	}
+	if got := client.CircuitState("carriers"); got != CircuitClosed {
+		t.Errorf("state = %s, want closed", got)
+	}
+}
+
+func TestCircuitBreakerRecovers(t *testing.T) {
+	srv := newBreakerServer(t)
+	srv.status.Store(http.StatusInternalServerError)
+	client := newBreakerClient(srv.URL, testBreakerSettings)
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		client.Carriers.List(ctx)
+	}
+	if got := client.CircuitState("carriers"); got != CircuitOpen {
+		t.Fatalf("state = %s, want open", got)
+	}
+
+	time.Sleep(testBreakerSettings.OpenTimeout)
+	if got := client.CircuitState("carriers"); got != CircuitHalfOpen {
+		t.Fatalf("state after OpenTimeout = %s, want half-open", got)
+	}
+
+	srv.status.Store(http.StatusOK)
+	if _, err := client.Carriers.List(ctx); err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if got := client.CircuitState("carriers"); got != CircuitClosed {
+		t.Errorf("state after successful probe = %s, want closed", got)
+	}
+}
+
+func TestCircuitBreakerFailedProbeReopens(t *testing.T) {
+	srv := newBreakerServer(t)
+	srv.status.Store(http.StatusBadGateway)
+	client := newBreakerClient(srv.URL, testBreakerSettings)
+	ctx := context.Background()
+
+	for i := 0; i < 4; i++ {
+		client.Carriers.List(ctx)
+	}
+	time.Sleep(testBreakerSettings.OpenTimeout)
+
+	if _, err := client.Carriers.List(ctx); errors.Is(err, ErrCircuitOpen) || !errors.Is(err, ErrServer) {
+		t.Fatalf("probe: err = %v, want a server error", err)
+	}
+	if got := client.CircuitState("carriers"); got != CircuitOpen {
+		t.Errorf("state after failed probe = %s, want open", got)
+	}
+	if _, err := client.Carriers.List(ctx); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("err = %v, want ErrCircuitOpen", err)
+	}
+}
+
+func TestCircuitBreakerOneProbeAtATime(t *testing.T) {
+	srv := newBreakerServer(t)
+	srv.status.Store(http.StatusServiceUnavailable)
+	client := newBreakerClient(srv.URL, testBreakerSettings)
+
+	for i := 0; i < 4; i++ {
+		client.Carriers.List(context.Background())
+	}
+	time.Sleep(testBreakerSettings.OpenTimeout)
+
+	srv.status.Store(http.StatusOK)
+	srv.delay.Store(int64(100 * time.Millisecond))
+	done := make(chan error)
+	go func() {
+		_, err := client.Carriers.List(context.Background())
+		done <- err
+	}()
+	for srv.hits.Load() < 5 {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := client.Carriers.List(context.Background()); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("second call while probing: err = %v, want ErrCircuitOpen", err)
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("probe: %v", err)
+	}
+	if got := client.CircuitState("carriers"); got != CircuitClosed {
+		t.Errorf("state = %s, want closed", got)
+	}
+}
+
+func TestCircuitBreakerDeadlines(t *testing.T) {
+	srv := newBreakerServer(t)
+	srv.delay.Store(int64(time.Second))
+	client := newBreakerClient(srv.URL, testBreakerSettings)
+
+	for i := 0; i < 4; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err := client.Carriers.List(ctx)
+		cancel()
+		if !errors.Is(err, ErrTimeout) {
+			t.Fatalf("err = %v, want ErrTimeout", err)
+		}
+	}
+	if got := client.CircuitState("carriers"); got != CircuitOpen {
+		t.Errorf("state after deadline expiries = %s, want open", got)
+	}
+}
+
+func TestCircuitBreakerIgnoresCanceledCalls(t *testing.T) {
+	srv := newBreakerServer(t)
+	srv.delay.Store(int64(time.Second))
+	client := newBreakerClient(srv.URL, testBreakerSettings)
+
+	for i := 0; i < 4; i++ {
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(10*time.Millisecond, cancel)
+		if _, err := client.Carriers.List(ctx); err == nil {
+			t.Fatal("canceled call succeeded")
+		}
+	}
+	if got := client.CircuitState("carriers"); got != CircuitClosed {
+		t.Errorf("state after canceled calls = %s, want closed", got)
+	}
+}
+
+func TestCircuitBreakerIgnoresRateLimiterTimeouts(t *testing.T) {
+	srv := newBreakerServer(t)
+	client := NewClient("test_key", WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{}),
+		WithCircuitBreaker(testBreakerSettings), WithRateLimit(1, 1))
+
+	if _, err := client.Carriers.List(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// The bucket is empty, so these time out waiting for a token
+	for i := 0; i < 4; i++ {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err := client.Carriers.List(ctx)
+		cancel()
+		if !errors.Is(err, ErrTimeout) {
+			t.Fatalf("err = %v, want ErrTimeout", err)
+		}
+	}
+	if hits := srv.hits.Load(); hits != 1 {
+		t.Errorf("server saw %d requests, want 1", hits)
+	}
+	if got := client.CircuitState("carriers"); got != CircuitClosed {
+		t.Errorf("state after rate limiter timeouts = %s, want closed", got)
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
 	"time"
 
 	"github.com/go-resty/resty/v2"
@@ -20,10 +23,33 @@ const (
 
 // Client is the main atoship API client
 type Client struct {
-	apiKey     string
-	baseURL    string
-	httpClient *resty.Client
-	debug      bool
+	apiKey      string
+	baseURL     string
+	timeout     time.Duration
+	timeoutSet  bool
+	httpClient  *resty.Client
+	baseClient  *http.Client
+	transport   http.RoundTripper
+	debug       bool
+	retryPolicy RetryPolicy
+	limiter     rateLimiter
+	middleware  []Middleware
+
+	debugLogger  Logger
+	redactFields []string
+	logger       *slog.Logger
+	logLevels    logLevels
+
+	instrumentation   []Instrumentation
+	compressThreshold int
+
+	circuitDefault  *CircuitBreakerSettings
+	circuitSettings map[string]CircuitBreakerSettings
+	breakers        map[string]*circuitBreaker
+
+	environment    Environment
+	environmentSet bool
+	liveGuard      bool
 
 	// Services
 	Orders    *OrdersService
@@ -42,11 +68,11 @@ type ClientOption func(*Client)
 // NewClient creates a new atoship API client
 func NewClient(apiKey string, opts ...ClientOption) *Client {
 	client := &Client{
-		apiKey:  apiKey,
-		baseURL: DefaultBaseURL,
-		httpClient: resty.New().
-			SetTimeout(DefaultTimeout).
-			SetHeader("User-Agent", "atoship-go-sdk/"+Version),
+		apiKey:       apiKey,
+		baseURL:      DefaultBaseURL,
+		timeout:      DefaultTimeout,
+		redactFields: DefaultRedactFields,
+		logLevels:    logLevels{success: DefaultSuccessLogLevel, failure: DefaultFailureLogLevel},
 	}
 
 	// Apply options
@@ -54,13 +80,49 @@ func NewClient(apiKey string, opts ...ClientOption) *Client {
 		opt(client)
 	}
 
+	if !client.environmentSet {
+		client.environment = environmentForKey(apiKey)
+	}
+	client.breakers = client.newBreakers()
+
+	// Build the HTTP client, copying a supplied one so that it is never
+	// modified
+	if client.baseClient != nil {
+		hc := *client.baseClient
+		client.httpClient = resty.NewWithClient(&hc)
+	} else {
+		client.httpClient = resty.New()
+	}
+	if client.transport != nil {
+		client.httpClient.SetTransport(client.transport)
+	}
+	if client.baseClient == nil || client.timeoutSet {
+		client.httpClient.SetTimeout(client.timeout)
+	}
+	client.httpClient.
+		SetBaseURL(client.baseURL).
+		SetHeader("User-Agent", "atoship-go-sdk/"+Version)
+
 	// Set up authentication
 	client.httpClient.SetHeader("X-API-Key", apiKey)
 	client.httpClient.SetHeader("Content-Type", "application/json")
 
-	// Set debug mode
+	// Install the middleware chain around the underlying transport, with
+	// debug logging innermost so it sees what goes on the wire, apart from
+	// compression
+	chain := client.middleware[:len(client.middleware):len(client.middleware)]
 	if client.debug {
-		client.httpClient.SetDebug(true)
+		if client.debugLogger == nil {
+			client.debugLogger = log.Default()
+		}
+		chain = append(chain, debugMiddleware(client.debugLogger, apiKey, client.redactFields))
+	}
+	if client.compressThreshold > 0 {
+		chain = append(chain, compressionMiddleware(client.compressThreshold))
+	}
+	if len(chain) > 0 {
+		hc := client.httpClient.GetClient()
+		client.httpClient.SetTransport(chainTransport(hc.Transport, chain))
 	}
 
 	// Initialize services
@@ -80,29 +142,31 @@ func NewClient(apiKey string, opts ...ClientOption) *Client {
 func WithBaseURL(url string) ClientOption {
 	return func(c *Client) {
 		c.baseURL = url
-		c.httpClient.SetBaseURL(url)
 	}
 }
 
 // WithTimeout sets a custom request timeout
 func WithTimeout(timeout time.Duration) ClientOption {
 	return func(c *Client) {
-		c.httpClient.SetTimeout(timeout)
+		c.timeout = timeout
+		c.timeoutSet = true
 	}
 }
 
-// WithRetryCount sets the number of retry attempts
+// WithRetryCount sets the number of retry attempts, using
+// DefaultRetryPolicy for everything else
 func WithRetryCount(count int) ClientOption {
-	return func(c *Client) {
-		c.httpClient.SetRetryCount(count)
-	}
+	policy := DefaultRetryPolicy()
+	policy.MaxRetries = count
+	return WithRetryPolicy(policy)
 }
 
-// WithDebug enables debug mode
+// WithDebug enables debug mode, logging each request and response to the
+// standard logger with secrets redacted. Use WithDebugLogger to choose
+// another logger.
 func WithDebug(debug bool) ClientOption {
 	return func(c *Client) {
 		c.debug = debug
-		c.httpClient.SetDebug(debug)
 	}
 }
 
@@ -111,6 +175,8 @@ type APIResponse struct {
 	Success   bool            `json:"success"`
 	Data      json.RawMessage `json:"data,omitempty"`
 	Error     string          `json:"error,omitempty"`
+	Code      string          `json:"code,omitempty"`
+	Details   any             `json:"details,omitempty"`
 	RequestID string          `json:"requestId,omitempty"`
 	Timestamp string          `json:"timestamp,omitempty"`
 }
@@ -124,100 +190,228 @@ type PaginatedResponse struct {
 	HasMore bool            `json:"hasMore"`
 }
 
-// APIError represents an API error
-type APIError struct {
-	Code       string `json:"code"`
-	Message    string `json:"message"`
-	StatusCode int    `json:"statusCode"`
-	RequestID  string `json:"requestId"`
-	Details    any    `json:"details,omitempty"`
-}
+// makeRequest performs an HTTP request, retrying failed attempts according
+// to the client's retry policy
+func (c *Client) makeRequest(ctx context.Context, method, path string, body interface{}, result interface{}) (err error) {
+	switch method {
+	case "GET", "POST", "PUT", "DELETE", "PATCH":
+	default:
+		return fmt.Errorf("unsupported HTTP method: %s", method)
+	}
 
-// Error implements the error interface
-func (e *APIError) Error() string {
-	return fmt.Sprintf("atoship API error: %s (code: %s, status: %d)", e.Message, e.Code, e.StatusCode)
-}
+	header := http.Header{}
+	if method == "POST" || method == "PATCH" {
+		header.Set(headerIdempotencyKey, idempotencyKey(ctx))
+	}
+	info := &RequestInfo{Method: method, Header: header}
+	info.Path, info.Operation = matchRoute(method, path)
+	for _, inst := range c.instrumentation {
+		ctx = inst.OnRequestStart(ctx, info)
+	}
+	started := time.Now()
+
+	var meta ResponseMeta
+	var sent, received int64
+	var wire *wireSizes
+	if c.compressThreshold > 0 {
+		wire = &wireSizes{}
+		ctx = withWireSizes(ctx, wire)
+	}
+	if out := responseMetaFrom(ctx); out != nil {
+		defer func() {
+			meta.Latency = time.Since(started)
+			*out = meta
+		}()
+	}
+	if c.logger != nil {
+		defer func() {
+			c.logCall(ctx, info, started, &meta, err)
+		}()
+	}
+	if len(c.instrumentation) > 0 {
+		defer func() {
+			c.endInstrumentation(ctx, info, &meta, sent, received, started, err)
+		}()
+	}
+	if breaker := c.breakerFor(info.Operation); breaker != nil {
+		probe, rejected := breaker.allow()
+		if rejected != nil {
+			return rejected
+		}
+		defer func() {
+			// A call that never left the rate limiter queue says nothing
+			// about the API
+			if meta.Attempts == 0 {
+				breaker.release(probe)
+				return
+			}
+			breaker.record(ctx, probe, err)
+		}()
+	}
 
-// Common error codes
-const (
-	ErrCodeValidation      = "VALIDATION_ERROR"
-	ErrCodeAuthentication  = "AUTHENTICATION_ERROR"
-	ErrCodeAuthorization   = "AUTHORIZATION_ERROR"
-	ErrCodeNotFound        = "NOT_FOUND"
-	ErrCodeRateLimit       = "RATE_LIMIT_EXCEEDED"
-	ErrCodeServerError     = "SERVER_ERROR"
-	ErrCodeNetworkError    = "NETWORK_ERROR"
-	ErrCodeTimeoutError    = "TIMEOUT_ERROR"
-	ErrCodeConfigError     = "CONFIGURATION_ERROR"
-)
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.wait(ctx); err != nil {
+			apiErr := transportError(err)
+			apiErr.Attempts = attempt - 1
+			return apiErr
+		}
+
+		meta.Attempts = attempt
+		resp, err := c.execute(ctx, method, path, body, header)
+		if resp != nil && resp.RawResponse != nil {
+			c.limiter.observe(resp.Header())
+			sent, received = resp.Request.RawRequest.ContentLength, resp.Size()
+			if wire != nil && wire.sent >= 0 {
+				sent = wire.sent
+			}
+			if wire != nil && wire.received >= 0 {
+				received = wire.received
+			}
+		}
+		meta.observeResponse(resp)
+		err = c.parseResponse(resp, err, result, &meta)
+		if err == nil {
+			return nil
+		}
+
+		apiErr, ok := err.(*APIError)
+		if !ok {
+			return err
+		}
+		apiErr.Attempts = attempt
+
+		// Never retry once the caller has given up
+		if ctx.Err() != nil {
+			return apiErr
+		}
 
-// makeRequest performs an HTTP request
-func (c *Client) makeRequest(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+		wait, retry := c.retryPolicy.retryDelay(attempt, started, method, header, resp, apiErr.isTransport())
+		if !retry {
+			return apiErr
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return apiErr
+		case <-timer.C:
+		}
+	}
+}
+
+// execute sends a single attempt of a request
+func (c *Client) execute(ctx context.Context, method, path string, body interface{}, header http.Header) (*resty.Response, error) {
 	req := c.httpClient.R().
-		SetContext(ctx).
-		SetResult(&APIResponse{})
+		SetContext(ctx)
+
+	for key := range header {
+		req.SetHeader(key, header.Get(key))
+	}
 
 	if body != nil {
 		req.SetBody(body)
 	}
 
-	var resp *resty.Response
-	var err error
-
-	switch method {
-	case "GET":
-		resp, err = req.Get(path)
-	case "POST":
-		resp, err = req.Post(path)
-	case "PUT":
-		resp, err = req.Put(path)
-	case "DELETE":
-		resp, err = req.Delete(path)
-	case "PATCH":
-		resp, err = req.Patch(path)
-	default:
-		return fmt.Errorf("unsupported HTTP method: %s", method)
-	}
+	return req.Execute(method, path)
+}
 
+// parseResponse converts the outcome of a single attempt into an error, or
+// decodes the response data into result. Envelope metadata is recorded on
+// meta.
+func (c *Client) parseResponse(resp *resty.Response, err error, result interface{}, meta *ResponseMeta) error {
 	if err != nil {
-		return &APIError{
-			Code:    ErrCodeNetworkError,
-			Message: err.Error(),
-		}
+		return transportError(err)
 	}
 
 	// Check for HTTP errors
 	if resp.IsError() {
-		var apiErr APIError
-		if err := json.Unmarshal(resp.Body(), &apiErr); err != nil {
-			return &APIError{
-				Code:       ErrCodeServerError,
-				Message:    string(resp.Body()),
-				StatusCode: resp.StatusCode(),
-			}
+		apiErr := errorFromBody(resp.StatusCode(), resp.Body())
+		if apiErr.RequestID == "" {
+			apiErr.RequestID = meta.RequestID
 		}
-		apiErr.StatusCode = resp.StatusCode()
-		return &apiErr
+		meta.RequestID = apiErr.RequestID
+		return apiErr
 	}
 
 	// Parse successful response
-	apiResp := resp.Result().(*APIResponse)
+	var apiResp APIResponse
+	if err := json.Unmarshal(resp.Body(), &apiResp); err != nil {
+		return &DecodeError{
+			StatusCode: resp.StatusCode(),
+			RequestID:  meta.RequestID,
+			Body:       resp.Body(),
+			Err:        err,
+		}
+	}
+	if apiResp.RequestID != "" {
+		meta.RequestID = apiResp.RequestID
+	}
+	meta.Timestamp = apiResp.Timestamp
+
 	if !apiResp.Success {
+		code := apiResp.Code
+		if code == "" {
+			code = ErrCodeAPIError
+		}
 		return &APIError{
-			Code:      "API_ERROR",
-			Message:   apiResp.Error,
-			RequestID: apiResp.RequestID,
+			Code:       code,
+			Message:    apiResp.Error,
+			StatusCode: resp.StatusCode(),
+			RequestID:  meta.RequestID,
+			Details:    apiResp.Details,
 		}
 	}
 
 	// Unmarshal data into result
 	if result != nil && apiResp.Data != nil {
-		return json.Unmarshal(apiResp.Data, result)
+		if err := json.Unmarshal(apiResp.Data, result); err != nil {
+			return &DecodeError{
+				StatusCode: resp.StatusCode(),
+				RequestID:  meta.RequestID,
+				Body:       apiResp.Data,
+				Err:        err,
+			}
+		}
 	}
 
 	return nil
 }
 
+// errorFromBody builds an APIError from an HTTP error response, which may
+// carry either an error object or a failed APIResponse envelope
+func errorFromBody(status int, body []byte) *APIError {
+	var payload struct {
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+		Error     string `json:"error"`
+		RequestID string `json:"requestId"`
+		Details   any    `json:"details"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return &APIError{
+			Code:       codeForStatus(status),
+			Message:    string(body),
+			StatusCode: status,
+		}
+	}
+
+	apiErr := &APIError{
+		Code:       payload.Code,
+		Message:    payload.Message,
+		StatusCode: status,
+		RequestID:  payload.RequestID,
+		Details:    payload.Details,
+	}
+	if apiErr.Code == "" {
+		apiErr.Code = codeForStatus(status)
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = payload.Error
+	}
+	return apiErr
+}
+
 // get performs a GET request
 func (c *Client) get(ctx context.Context, path string, result interface{}) error {
 	return c.makeRequest(ctx, "GET", path, nil, result)
@@ -241,4 +435,4 @@ func (c *Client) delete(ctx context.Context, path string) error {
 // patch performs a PATCH request
 func (c *Client) patch(ctx context.Context, path string, body, result interface{}) error {
 	return c.makeRequest(ctx, "PATCH", path, body, result)
-}
\ No newline at end of file
+}
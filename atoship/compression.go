@@ -0,0 +1,155 @@
+package atoship
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// DefaultCompressionThreshold is the smallest request body compressed by
+// WithCompression when given no threshold
+const DefaultCompressionThreshold = 1 << 10
+
+// WithCompression gzips request bodies of at least threshold bytes, such
+// as OrdersService.BulkCreate and TrackingService.BatchTrack payloads, and
+// asks for gzipped responses, which are decompressed transparently. A
+// threshold of zero or less uses DefaultCompressionThreshold.
+//
+// Compression happens after all middleware, which sees uncompressed
+// bodies. RequestResult.RequestBytes and ResponseBytes report the
+// compressed sizes.
+func WithCompression(threshold int) ClientOption {
+	return func(c *Client) {
+		if threshold <= 0 {
+			threshold = DefaultCompressionThreshold
+		}
+		c.compressThreshold = threshold
+	}
+}
+
+// wireSizesKey is the context key of the *wireSizes of a call
+type wireSizesKey struct{}
+
+// wireSizes records the body sizes of an attempt as sent and received,
+// which compression makes differ from what resty reports. A negative size
+// was not measured.
+type wireSizes struct {
+	sent     int64
+	received int64
+}
+
+// withWireSizes returns a copy of ctx in which compressionMiddleware
+// records body sizes to sizes
+func withWireSizes(ctx context.Context, sizes *wireSizes) context.Context {
+	return context.WithValue(ctx, wireSizesKey{}, sizes)
+}
+
+// compressionMiddleware gzips large request bodies and decompresses gzipped
+// responses
+func compressionMiddleware(threshold int) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			if err := compressRequest(req, threshold); err != nil {
+				return nil, err
+			}
+			if req.Header.Get("Accept-Encoding") == "" {
+				req.Header.Set("Accept-Encoding", "gzip")
+			}
+			sizes, _ := req.Context().Value(wireSizesKey{}).(*wireSizes)
+			if sizes != nil {
+				*sizes = wireSizes{sent: req.ContentLength, received: -1}
+			}
+
+			resp, err := next(req)
+			if err != nil {
+				return resp, err
+			}
+			if strings.EqualFold(resp.Header.Get("Content-Encoding"), "gzip") {
+				if sizes != nil {
+					sizes.received = 0
+					resp.Body = &countingBody{ReadCloser: resp.Body, n: &sizes.received}
+				}
+				zr, err := gzip.NewReader(resp.Body)
+				if err != nil {
+					resp.Body.Close()
+					return nil, err
+				}
+				resp.Body = &gzipBody{Reader: zr, body: resp.Body}
+				resp.Header.Del("Content-Encoding")
+				resp.Header.Del("Content-Length")
+				resp.ContentLength = -1
+				resp.Uncompressed = true
+			}
+			return resp, nil
+		}
+	}
+}
+
+// compressRequest replaces the body of req with its gzipped form if it is
+// at least threshold bytes and not already encoded
+func compressRequest(req *http.Request, threshold int) error {
+	if req.Body == nil || req.Body == http.NoBody || req.Header.Get("Content-Encoding") != "" {
+		return nil
+	}
+	if req.ContentLength >= 0 && req.ContentLength < int64(threshold) {
+		return nil
+	}
+
+	body, err := io.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return err
+	}
+	if len(body) < threshold {
+		req.Body = io.NopCloser(bytes.NewReader(body))
+		req.ContentLength = int64(len(body))
+		return nil
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := zw.Write(body); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	compressed := buf.Bytes()
+
+	req.Header.Set("Content-Encoding", "gzip")
+	req.ContentLength = int64(len(compressed))
+	req.Body = io.NopCloser(bytes.NewReader(compressed))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(compressed)), nil
+	}
+	return nil
+}
+
+// countingBody adds the number of bytes read from a body to n
+type countingBody struct {
+	io.ReadCloser
+	n *int64
+}
+
+// Read implements io.Reader
+func (b *countingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	*b.n += int64(n)
+	return n, err
+}
+
+// gzipBody decompresses a response body, closing the original on Close
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+// Close closes the decompressor and the underlying body
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
@@ -0,0 +1,180 @@
+package atoship_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+	"github.com/atoship-LLC/atoship-go/atoship/atoshiptest"
+)
+
+// resultRecorder is an Instrumentation keeping the last RequestResult
+type resultRecorder struct{ last *atoship.RequestResult }
+
+func (r *resultRecorder) OnRequestStart(ctx context.Context, _ *atoship.RequestInfo) context.Context {
+	return ctx
+}
+
+func (r *resultRecorder) OnRequestEnd(_ context.Context, _ *atoship.RequestInfo, result *atoship.RequestResult) {
+	r.last = result
+}
+
+// variedOrders returns n orders with differing recipients, so that they do
+// not compress unrealistically well
+func variedOrders(n int) []*atoship.CreateOrderRequest {
+	rng := rand.New(rand.NewSource(1))
+	first := []string{"Jane", "John", "Maria", "Wei", "Aisha", "Carlos", "Olga", "Kenji", "Fatima", "Liam"}
+	last := []string{"Doe", "Smith", "Garcia", "Chen", "Khan", "Lopez", "Ivanova", "Tanaka", "Hassan", "Murphy"}
+	streets := []string{"Main St", "Oak Ave", "Pine Rd", "Maple Dr", "Cedar Ln", "Elm St", "Lake Blvd", "Hill Ct"}
+	cities := [][3]string{{"Austin", "TX", "787"}, {"Denver", "CO", "802"}, {"Seattle", "WA", "981"}, {"Boston", "MA", "021"}, {"Miami", "FL", "331"}}
+
+	orders := make([]*atoship.CreateOrderRequest, n)
+	for i := range orders {
+		city := cities[rng.Intn(len(cities))]
+		name := first[rng.Intn(len(first))] + " " + last[rng.Intn(len(last))]
+		orders[i] = &atoship.CreateOrderRequest{
+			OrderNumber:      fmt.Sprintf("ORD-%06d", rng.Intn(1000000)),
+			RecipientName:    name,
+			RecipientStreet1: fmt.Sprintf("%d %s", 1+rng.Intn(9999), streets[rng.Intn(len(streets))]),
+			RecipientCity:    city[0],
+			RecipientState:   city[1],
+			RecipientPostal:  fmt.Sprintf("%s%02d", city[2], rng.Intn(100)),
+			RecipientCountry: "US",
+			RecipientEmail:   strings.ToLower(strings.ReplaceAll(name, " ", ".")) + "@example.com",
+			RecipientPhone:   fmt.Sprintf("+1 555 %03d %04d", rng.Intn(1000), rng.Intn(10000)),
+			Items: []atoship.OrderItem{{
+				Name:      fmt.Sprintf("Widget %c", 'A'+rng.Intn(26)),
+				SKU:       fmt.Sprintf("WID-%04d", rng.Intn(10000)),
+				Quantity:  1 + rng.Intn(5),
+				UnitPrice: float64(100+rng.Intn(9900)) / 100,
+			}},
+		}
+	}
+	return orders
+}
+
+func TestCompressionRoundTrip(t *testing.T) {
+	var received int
+	var gzipped []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		received = len(raw)
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("Content-Encoding = %q, want gzip", r.Header.Get("Content-Encoding"))
+		}
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			t.Errorf("Accept-Encoding = %q, want gzip", r.Header.Get("Accept-Encoding"))
+		}
+		zr, err := gzip.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			t.Fatalf("request body is not gzipped: %v", err)
+		}
+		var req struct {
+			Orders []atoship.CreateOrderRequest `json:"orders"`
+		}
+		if err := json.NewDecoder(zr).Decode(&req); err != nil {
+			t.Fatalf("decoding request: %v", err)
+		}
+
+		resp := atoship.BulkCreateResponse{Failed: []atoship.FailedOrder{}}
+		for i, o := range req.Orders {
+			resp.Successful = append(resp.Successful, atoship.Order{ID: fmt.Sprintf("ord_%d", i+1), OrderNumber: o.OrderNumber})
+		}
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		json.NewEncoder(zw).Encode(map[string]any{"success": true, "data": resp})
+		zw.Close()
+		gzipped = buf.Bytes()
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(gzipped)
+	}))
+	defer srv.Close()
+
+	rec := &resultRecorder{}
+	client := atoship.NewClient("test_key", atoship.WithBaseURL(srv.URL), atoship.WithCompression(0), atoship.WithInstrumentation(rec))
+	orders := variedOrders(50)
+	resp, err := client.Orders.BulkCreate(context.Background(), orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Successful) != len(orders) || resp.Successful[49].OrderNumber != orders[49].OrderNumber {
+		t.Fatalf("decoded %d orders, want %d", len(resp.Successful), len(orders))
+	}
+
+	uncompressed, _ := json.Marshal(map[string]any{"orders": orders})
+	if rec.last.RequestBytes != int64(received) || received >= len(uncompressed) {
+		t.Errorf("RequestBytes = %d, server received %d, uncompressed %d", rec.last.RequestBytes, received, len(uncompressed))
+	}
+	if rec.last.ResponseBytes != int64(len(gzipped)) {
+		t.Errorf("ResponseBytes = %d, want %d gzipped bytes", rec.last.ResponseBytes, len(gzipped))
+	}
+}
+
+func TestCompressionThreshold(t *testing.T) {
+	client, srv := atoshiptest.NewClient(atoship.WithCompression(4 << 10))
+	defer srv.Close()
+
+	ctx := context.Background()
+	if _, err := client.Orders.Create(ctx, variedOrders(1)[0]); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Orders.BulkCreate(ctx, variedOrders(100)); err != nil {
+		t.Fatal(err)
+	}
+
+	requests := srv.Requests()
+	if len(requests) != 2 {
+		t.Fatalf("server saw %d requests, want 2", len(requests))
+	}
+	small, bulk := requests[0], requests[1]
+	if small.Header.Get("Content-Encoding") != "" || small.BodySize != len(small.Body) {
+		t.Errorf("small request was compressed: %d of %d bytes", small.BodySize, len(small.Body))
+	}
+	if bulk.Header.Get("Content-Encoding") != "gzip" || bulk.BodySize >= len(bulk.Body) {
+		t.Errorf("bulk request was not compressed: %d of %d bytes", bulk.BodySize, len(bulk.Body))
+	}
+}
+
+// BenchmarkBulkCreateCompression reports the request bytes the server
+// receives for a BulkCreate of 1,000 varied orders, with and without
+// WithCompression
+func BenchmarkBulkCreateCompression(b *testing.B) {
+	orders := variedOrders(1000)
+	for _, bb := range []struct {
+		name string
+		opts []atoship.ClientOption
+	}{
+		{"uncompressed", nil},
+		{"gzip", []atoship.ClientOption{atoship.WithCompression(0)}},
+	} {
+		b.Run(bb.name, func(b *testing.B) {
+			client, srv := atoshiptest.NewClient(bb.opts...)
+			defer srv.Close()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := client.Orders.BulkCreate(context.Background(), orders); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.StopTimer()
+
+			var sent int
+			for _, r := range srv.Requests() {
+				sent += r.BodySize
+			}
+			b.ReportMetric(float64(sent)/float64(b.N), "req-bytes/op")
+		})
+	}
+}
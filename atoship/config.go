@@ -0,0 +1,332 @@
+package atoship
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Environment variables read by LoadConfig
+const (
+	EnvAPIKey      = "ATOSHIP_API_KEY"
+	EnvBaseURL     = "ATOSHIP_BASE_URL"
+	EnvTimeout     = "ATOSHIP_TIMEOUT"
+	EnvMaxRetries  = "ATOSHIP_MAX_RETRIES"
+	EnvDebug       = "ATOSHIP_DEBUG"
+	EnvEnvironment = "ATOSHIP_ENVIRONMENT"
+	EnvLiveGuard   = "ATOSHIP_LIVE_GUARD"
+	EnvProfile     = "ATOSHIP_PROFILE"
+	EnvConfigFile  = "ATOSHIP_CONFIG_FILE"
+)
+
+// DefaultProfile is the config file profile used when none is named
+const DefaultProfile = "default"
+
+// Config holds client settings loaded from the environment and a config
+// file
+type Config struct {
+	// Profile is the config file profile the settings were read from
+	Profile string
+	// APIKey authenticates requests; required
+	APIKey string
+	// BaseURL overrides DefaultBaseURL
+	BaseURL string
+	// Timeout overrides DefaultTimeout
+	Timeout time.Duration
+	// MaxRetries enables retries with DefaultRetryPolicy when positive
+	MaxRetries int
+	// Debug enables debug logging
+	Debug bool
+	// Environment, when set, is passed to WithEnvironment
+	Environment Environment
+	// LiveGuard enables WithLiveGuard
+	LiveGuard bool
+}
+
+// DefaultConfigPath returns the default config file location,
+// ~/.config/atoship/config on Linux
+func DefaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "atoship", "config"), nil
+}
+
+// LoadConfig reads settings for the named profile from the config file,
+// then applies ATOSHIP_* environment variables on top. The file is taken
+// from ATOSHIP_CONFIG_FILE or DefaultConfigPath and may be absent. An empty
+// profile selects ATOSHIP_PROFILE, or DefaultProfile.
+//
+// The config file is INI-style, with one section per profile. Settings in
+// the default section apply to every profile unless overridden:
+//
+//	[default]
+//	timeout = 20s
+//
+//	[sandbox]
+//	api_key = test_...
+//
+//	[production]
+//	api_key = live_...
+//	max_retries = 3
+//
+// The recognized keys are api_key, base_url, timeout, max_retries, debug,
+// environment (sandbox or live) and live_guard. Unknown keys, malformed
+// values and a named profile missing from the file are reported as errors
+// matching ErrConfig. Settings may still be changed before the Config is
+// passed to NewClientFromConfig, which validates them.
+func LoadConfig(profile string) (*Config, error) {
+	path := os.Getenv(EnvConfigFile)
+	explicit := path != ""
+	if !explicit {
+		var err error
+		if path, err = DefaultConfigPath(); err != nil {
+			path = ""
+		}
+	}
+	return loadConfig(path, explicit, profile)
+}
+
+// LoadConfigFile is like LoadConfig but reads the config file at path,
+// which must exist
+func LoadConfigFile(path, profile string) (*Config, error) {
+	return loadConfig(path, true, profile)
+}
+
+func loadConfig(path string, mustExist bool, profile string) (*Config, error) {
+	named := profile != "" || os.Getenv(EnvProfile) != ""
+	if profile == "" {
+		profile = os.Getenv(EnvProfile)
+	}
+	if profile == "" {
+		profile = DefaultProfile
+	}
+	cfg := &Config{Profile: profile}
+
+	var sections map[string]map[string]string
+	if path != "" {
+		var err error
+		sections, err = readConfigFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			if mustExist {
+				return nil, configError("config file %s not found", path)
+			}
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if named && profile != DefaultProfile && sections[profile] == nil {
+		return nil, configError("profile %q not found in %s", profile, path)
+	}
+	for _, name := range []string{DefaultProfile, profile} {
+		for key, value := range sections[name] {
+			if err := cfg.set(key, value); err != nil {
+				return nil, configError("%s: [%s] %v", path, name, err)
+			}
+		}
+	}
+
+	env := []struct{ name, key string }{
+		{EnvAPIKey, "api_key"},
+		{EnvBaseURL, "base_url"},
+		{EnvTimeout, "timeout"},
+		{EnvMaxRetries, "max_retries"},
+		{EnvDebug, "debug"},
+		{EnvEnvironment, "environment"},
+		{EnvLiveGuard, "live_guard"},
+	}
+	for _, e := range env {
+		if value := os.Getenv(e.name); value != "" {
+			if err := cfg.set(e.key, value); err != nil {
+				return nil, configError("%s: %v", e.name, err)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// set applies one setting by its config file key
+func (c *Config) set(key, value string) error {
+	switch key {
+	case "api_key":
+		c.APIKey = value
+	case "base_url":
+		c.BaseURL = value
+	case "timeout":
+		d, err := parseTimeout(value)
+		if err != nil {
+			return fmt.Errorf("invalid timeout %q", value)
+		}
+		c.Timeout = d
+	case "max_retries":
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid max_retries %q", value)
+		}
+		c.MaxRetries = n
+	case "debug":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid debug %q", value)
+		}
+		c.Debug = b
+	case "environment":
+		env, err := parseEnvironment(value)
+		if err != nil {
+			return err
+		}
+		c.Environment = env
+	case "live_guard":
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid live_guard %q", value)
+		}
+		c.LiveGuard = b
+	default:
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	return nil
+}
+
+// parseTimeout accepts a duration such as "30s" or a number of seconds
+func parseTimeout(value string) (time.Duration, error) {
+	if secs, err := strconv.ParseFloat(value, 64); err == nil {
+		return time.Duration(secs * float64(time.Second)), nil
+	}
+	return time.ParseDuration(value)
+}
+
+// Validate reports the first invalid setting as an error matching ErrConfig
+func (c *Config) Validate() error {
+	if c.APIKey == "" {
+		return configError("no API key: set %s or api_key in profile %q", EnvAPIKey, c.Profile)
+	}
+	if c.BaseURL != "" {
+		u, err := url.Parse(c.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return configError("invalid base URL %q", c.BaseURL)
+		}
+	}
+	if c.Timeout < 0 {
+		return configError("timeout must not be negative")
+	}
+	if c.MaxRetries < 0 {
+		return configError("max_retries must not be negative")
+	}
+	if c.Environment != "" {
+		if _, err := parseEnvironment(string(c.Environment)); err != nil {
+			return configError("%v", err)
+		}
+	}
+	return nil
+}
+
+// Options returns the client options for the settings other than the API
+// key
+func (c *Config) Options() []ClientOption {
+	var opts []ClientOption
+	if c.BaseURL != "" {
+		opts = append(opts, WithBaseURL(c.BaseURL))
+	}
+	if c.Timeout > 0 {
+		opts = append(opts, WithTimeout(c.Timeout))
+	}
+	if c.MaxRetries > 0 {
+		opts = append(opts, WithRetryCount(c.MaxRetries))
+	}
+	if c.Debug {
+		opts = append(opts, WithDebug(true))
+	}
+	if c.Environment != "" {
+		opts = append(opts, WithEnvironment(c.Environment))
+	}
+	if c.LiveGuard {
+		opts = append(opts, WithLiveGuard(true))
+	}
+	return opts
+}
+
+// NewClientFromConfig validates cfg and creates a client from it. opts are
+// applied after the settings in cfg.
+func NewClientFromConfig(cfg *Config, opts ...ClientOption) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return NewClient(cfg.APIKey, append(cfg.Options(), opts...)...), nil
+}
+
+// NewClientFromEnv creates a client from LoadConfig("")
+func NewClientFromEnv(opts ...ClientOption) (*Client, error) {
+	cfg, err := LoadConfig("")
+	if err != nil {
+		return nil, err
+	}
+	return NewClientFromConfig(cfg, opts...)
+}
+
+// readConfigFile parses an INI-style config file into settings by profile.
+// Settings before the first section belong to DefaultProfile.
+func readConfigFile(path string) (map[string]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, err
+		}
+		return nil, configError("%v", err)
+	}
+	defer f.Close()
+
+	sections := map[string]map[string]string{}
+	section := DefaultProfile
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		switch {
+		case text == "" || text[0] == '#' || text[0] == ';':
+			continue
+		case text[0] == '[':
+			if !strings.HasSuffix(text, "]") {
+				return nil, configError("%s:%d: malformed section header", path, line)
+			}
+			section = strings.TrimSpace(text[1 : len(text)-1])
+			if strings.HasPrefix(section, "profile ") {
+				section = strings.TrimSpace(strings.TrimPrefix(section, "profile "))
+			}
+			if sections[section] == nil {
+				sections[section] = map[string]string{}
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(text, "=")
+		if !ok {
+			return nil, configError("%s:%d: expected key = value", path, line)
+		}
+		if sections[section] == nil {
+			sections[section] = map[string]string{}
+		}
+		sections[section][strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, configError("%s: %v", path, err)
+	}
+	return sections, nil
+}
+
+// configError returns an APIError with ErrCodeConfigError
+func configError(format string, args ...any) error {
+	return &APIError{
+		Code:    ErrCodeConfigError,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
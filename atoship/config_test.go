@@ -0,0 +1,254 @@
+package atoship
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// clearConfigEnv unsets the ATOSHIP_* variables for the duration of a test
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{EnvAPIKey, EnvBaseURL, EnvTimeout, EnvMaxRetries, EnvDebug, EnvEnvironment, EnvLiveGuard, EnvProfile, EnvConfigFile} {
+		t.Setenv(name, "")
+	}
+}
+
+// writeConfig writes a config file to a temporary directory and returns
+// its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+const testConfigFile = `
+# shared settings
+[default]
+api_key = test_default
+timeout = 20s
+
+[sandbox]
+api_key = "test_sandbox"
+environment = sandbox
+
+; AWS-style section name
+[profile production]
+api_key = 'live_production'
+max_retries = 3
+environment = live
+live_guard = true
+debug = true
+`
+
+func TestLoadConfigFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		profile string
+		env     map[string]string
+		want    Config
+		wantErr bool
+	}{
+		{
+			name: "default profile",
+			file: testConfigFile,
+			want: Config{Profile: "default", APIKey: "test_default", Timeout: 20 * time.Second},
+		},
+		{
+			name:    "named profile falls back to default",
+			file:    testConfigFile,
+			profile: "sandbox",
+			want:    Config{Profile: "sandbox", APIKey: "test_sandbox", Timeout: 20 * time.Second, Environment: EnvironmentSandbox},
+		},
+		{
+			name:    "profile section prefix",
+			file:    testConfigFile,
+			profile: "production",
+			want:    Config{Profile: "production", APIKey: "live_production", Timeout: 20 * time.Second, MaxRetries: 3, Debug: true, Environment: EnvironmentLive, LiveGuard: true},
+		},
+		{
+			name: "profile from the environment",
+			file: testConfigFile,
+			env:  map[string]string{EnvProfile: "sandbox"},
+			want: Config{Profile: "sandbox", APIKey: "test_sandbox", Timeout: 20 * time.Second, Environment: EnvironmentSandbox},
+		},
+		{
+			name:    "environment overrides file",
+			file:    testConfigFile,
+			profile: "production",
+			env:     map[string]string{EnvAPIKey: "test_env", EnvTimeout: "5", EnvMaxRetries: "0", EnvLiveGuard: "false", EnvBaseURL: "http://localhost:8080"},
+			want:    Config{Profile: "production", APIKey: "test_env", BaseURL: "http://localhost:8080", Timeout: 5 * time.Second, Debug: true, Environment: EnvironmentLive},
+		},
+		{
+			name: "settings before any section",
+			file: "api_key = test_top\ntimeout = 1.5\n",
+			want: Config{Profile: "default", APIKey: "test_top", Timeout: 1500 * time.Millisecond},
+		},
+		{name: "missing named profile", file: testConfigFile, profile: "staging", wantErr: true},
+		{name: "missing profile from the environment", file: testConfigFile, env: map[string]string{EnvProfile: "staging"}, wantErr: true},
+		{name: "unknown key", file: "[default]\napi_key = test_x\nregion = us\n", wantErr: true},
+		{name: "malformed line", file: "[default]\napi_key\n", wantErr: true},
+		{name: "malformed section", file: "[default\napi_key = test_x\n", wantErr: true},
+		{name: "malformed timeout", file: "timeout = soon\n", wantErr: true},
+		{name: "malformed max_retries", file: "max_retries = many\n", wantErr: true},
+		{name: "unknown environment", file: "environment = staging\n", wantErr: true},
+		{name: "malformed environment variable", file: testConfigFile, env: map[string]string{EnvDebug: "sometimes"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv(t)
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			cfg, err := LoadConfigFile(writeConfig(t, tt.file), tt.profile)
+			if tt.wantErr {
+				if !errors.Is(err, ErrConfig) {
+					t.Errorf("err = %v, want ErrConfig", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *cfg != tt.want {
+				t.Errorf("config = %+v\nwant     %+v", *cfg, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	clearConfigEnv(t)
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	if _, err := LoadConfigFile(missing, ""); !errors.Is(err, ErrConfig) {
+		t.Errorf("LoadConfigFile: err = %v, want ErrConfig", err)
+	}
+
+	// An explicit ATOSHIP_CONFIG_FILE must exist too
+	t.Setenv(EnvConfigFile, missing)
+	if _, err := LoadConfig(""); !errors.Is(err, ErrConfig) {
+		t.Errorf("LoadConfig: err = %v, want ErrConfig", err)
+	}
+}
+
+func TestParseTimeout(t *testing.T) {
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"30s", 30 * time.Second, true},
+		{"1m30s", 90 * time.Second, true},
+		{"30", 30 * time.Second, true},
+		{"0.25", 250 * time.Millisecond, true},
+		{"500ms", 500 * time.Millisecond, true},
+		{"", 0, false},
+		{"thirty", 0, false},
+		{"30 s", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := parseTimeout(tt.value)
+		if got != tt.want || (err == nil) != tt.ok {
+			t.Errorf("parseTimeout(%q) = %v, %v; want %v, ok %v", tt.value, got, err, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"valid", Config{APIKey: "test_x", BaseURL: "https://api.example.com", Timeout: time.Second, MaxRetries: 2, Environment: EnvironmentLive}, false},
+		{"no API key", Config{}, true},
+		{"base URL without scheme", Config{APIKey: "test_x", BaseURL: "api.example.com"}, true},
+		{"base URL with other scheme", Config{APIKey: "test_x", BaseURL: "ftp://api.example.com"}, true},
+		{"negative timeout", Config{APIKey: "test_x", Timeout: -time.Second}, true},
+		{"negative max_retries", Config{APIKey: "test_x", MaxRetries: -1}, true},
+		{"unknown environment", Config{APIKey: "test_x", Environment: "staging"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.cfg.Validate()
+			if tt.wantErr != (err != nil) || (err != nil && !errors.Is(err, ErrConfig)) {
+				t.Errorf("Validate = %v, want error %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewClientFromConfig(t *testing.T) {
+	client, err := NewClientFromConfig(&Config{
+		APIKey:      "live_x",
+		BaseURL:     "http://localhost:8080",
+		Timeout:     7 * time.Second,
+		Environment: EnvironmentLive,
+		LiveGuard:   true,
+	}, WithTimeout(3*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.baseURL != "http://localhost:8080" || client.Environment() != EnvironmentLive || !client.liveGuard {
+		t.Errorf("client = %+v", client)
+	}
+	if got := client.httpClient.GetClient().Timeout; got != 3*time.Second {
+		t.Errorf("Timeout = %v, want the option given after the config", got)
+	}
+
+	if _, err := NewClientFromConfig(&Config{}); !errors.Is(err, ErrConfig) {
+		t.Errorf("empty config: err = %v, want ErrConfig", err)
+	}
+}
+
+func TestNewClientFromEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		apiKey  string
+		timeout time.Duration
+		wantErr bool
+	}{
+		{name: "file profile", env: map[string]string{EnvProfile: "sandbox"}, apiKey: "test_sandbox", timeout: 20 * time.Second},
+		{name: "environment only", env: map[string]string{EnvConfigFile: "", EnvAPIKey: "test_env", EnvTimeout: "9s"}, apiKey: "test_env", timeout: 9 * time.Second},
+		{name: "environment over file", env: map[string]string{EnvAPIKey: "test_env"}, apiKey: "test_env", timeout: 20 * time.Second},
+		{name: "no API key", env: map[string]string{EnvConfigFile: ""}, wantErr: true},
+		{name: "unknown profile", env: map[string]string{EnvProfile: "staging"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearConfigEnv(t)
+			// Keep a config in the user's home directory out of the test
+			t.Setenv("HOME", t.TempDir())
+			t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+			t.Setenv(EnvConfigFile, writeConfig(t, testConfigFile))
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			client, err := NewClientFromEnv()
+			if tt.wantErr {
+				if !errors.Is(err, ErrConfig) {
+					t.Errorf("err = %v, want ErrConfig", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if client.apiKey != tt.apiKey {
+				t.Errorf("API key = %q, want %q", client.apiKey, tt.apiKey)
+			}
+			if got := client.httpClient.GetClient().Timeout; got != tt.timeout {
+				t.Errorf("Timeout = %v, want %v", got, tt.timeout)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package atoship
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Logger receives debug output. *log.Logger satisfies it.
+type Logger interface {
+	Printf(format string, v ...any)
+}
+
+// redactedValue replaces redacted values in debug output
+const redactedValue = "[REDACTED]"
+
+// Debug output limits
+const (
+	// debugMaxString is the longest string value logged in full; longer
+	// values, such as a base64 LabelPDF, are truncated
+	debugMaxString = 256
+	// debugMaxBody is the largest body logged in full
+	debugMaxBody = 64 << 10
+)
+
+// secretFields are JSON fields whose values are always redacted
+var secretFields = []string{"apiKey", "api_key", "secret", "password", "token"}
+
+// secretHeaders are headers whose values are always redacted
+var secretHeaders = []string{"X-Api-Key", "Authorization", "Atoship-Signature", "Cookie", "Set-Cookie"}
+
+// DefaultRedactFields are the JSON fields and query parameters holding
+// personal data that are redacted from debug output unless replaced with
+// WithRedactFields
+var DefaultRedactFields = []string{
+	"name", "company", "street1", "street2", "phone", "email",
+	"recipientName", "recipientCompany", "recipientStreet1", "recipientStreet2", "recipientPhone", "recipientEmail",
+	"senderName", "senderCompany", "senderStreet1", "senderStreet2", "senderPhone", "senderEmail",
+	"search", "q",
+}
+
+// WithDebugLogger enables debug output and writes it to logger. Each
+// request and response is logged with its headers and JSON body, after
+// redacting the API key, secrets and personal data, and truncating long
+// values such as label PDFs.
+func WithDebugLogger(logger Logger) ClientOption {
+	return func(c *Client) {
+		c.debug = logger != nil
+		c.debugLogger = logger
+	}
+}
+
+// WithRedactFields replaces DefaultRedactFields as the personal data
+// fields redacted from debug output. Field names are matched case
+// insensitively at any depth of a JSON body and against query parameter
+// names. API keys and secrets are always redacted.
+func WithRedactFields(fields ...string) ClientOption {
+	return func(c *Client) {
+		c.redactFields = append([]string(nil), fields...)
+	}
+}
+
+// debugMiddleware logs each request and response through logger
+func debugMiddleware(logger Logger, apiKey string, piiFields []string) Middleware {
+	fields := make(map[string]bool, len(secretFields)+len(piiFields))
+	for _, f := range secretFields {
+		fields[strings.ToLower(f)] = true
+	}
+	for _, f := range piiFields {
+		fields[strings.ToLower(f)] = true
+	}
+	r := &redactor{apiKey: apiKey, fields: fields}
+
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			var body []byte
+			if req.Body != nil && req.Body != http.NoBody {
+				if req.GetBody != nil {
+					if rc, err := req.GetBody(); err == nil {
+						body, _ = io.ReadAll(rc)
+						rc.Close()
+					}
+				} else {
+					body, _ = io.ReadAll(req.Body)
+					req.Body.Close()
+					req.Body = io.NopCloser(bytes.NewReader(body))
+				}
+			}
+			target := r.url(req.URL)
+			logger.Printf("atoship: --> %s %s\n%s", req.Method, target, r.dump(req.Header, body))
+
+			started := time.Now()
+			resp, err := next(req)
+			elapsed := time.Since(started).Round(time.Millisecond)
+			if err != nil {
+				msg := strings.ReplaceAll(err.Error(), req.URL.String(), target)
+				logger.Printf("atoship: <-- %s %s failed after %s: %s", req.Method, r.string(req.URL.Path), elapsed, r.string(msg))
+				return resp, err
+			}
+
+			body, readErr := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			if readErr != nil {
+				logger.Printf("atoship: <-- %s %s (%s): reading body: %v", resp.Status, r.string(req.URL.Path), elapsed, readErr)
+				return resp, nil
+			}
+			logger.Printf("atoship: <-- %s %s (%s)\n%s", resp.Status, r.string(req.URL.Path), elapsed, r.dump(resp.Header, body))
+			return resp, nil
+		}
+	}
+}
+
+// redactor strips secrets and personal data from debug output
+type redactor struct {
+	apiKey string
+	fields map[string]bool
+}
+
+// dump formats headers and a body for the debug log
+func (r *redactor) dump(header http.Header, body []byte) string {
+	var b strings.Builder
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value := strings.Join(header[k], ", ")
+		for _, secret := range secretHeaders {
+			if strings.EqualFold(k, secret) {
+				value = redactedValue
+			}
+		}
+		fmt.Fprintf(&b, "    %s: %s\n", k, r.string(value))
+	}
+	if len(body) > 0 {
+		fmt.Fprintf(&b, "    %s\n", r.body(body))
+	}
+	return b.String()
+}
+
+// body redacts a JSON body, or truncates any other body
+func (r *redactor) body(body []byte) string {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return truncate(r.string(string(body)), debugMaxBody)
+	}
+	out, err := json.Marshal(r.value(v))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes]", len(body))
+	}
+	return truncate(string(out), debugMaxBody)
+}
+
+func (r *redactor) value(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if r.fields[strings.ToLower(k)] {
+				switch child.(type) {
+				case map[string]any, []any, nil:
+				default:
+					val[k] = redactedValue
+					continue
+				}
+			}
+			val[k] = r.value(child)
+		}
+		return val
+	case []any:
+		for i, child := range val {
+			val[i] = r.value(child)
+		}
+		return val
+	case string:
+		return truncate(r.string(val), debugMaxString)
+	}
+	return v
+}
+
+// url formats u with the values of redacted query parameters replaced,
+// keeping the parameters in their original order
+func (r *redactor) url(u *url.URL) string {
+	if u.RawQuery == "" {
+		return r.string(u.String())
+	}
+	params := strings.Split(u.RawQuery, "&")
+	for i, param := range params {
+		key, _, _ := strings.Cut(param, "=")
+		if name, err := url.QueryUnescape(key); err == nil && r.fields[strings.ToLower(name)] {
+			params[i] = key + "=" + redactedValue
+		}
+	}
+	redacted := *u
+	redacted.RawQuery = strings.Join(params, "&")
+	return r.string(redacted.String())
+}
+
+// string replaces the API key wherever it appears
+func (r *redactor) string(s string) string {
+	if r.apiKey == "" {
+		return s
+	}
+	return strings.ReplaceAll(s, r.apiKey, redactedValue)
+}
+
+// truncate shortens s to at most n bytes, noting how much was dropped
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return fmt.Sprintf("%s...[%d bytes truncated]", s[:n], len(s)-n)
+}
@@ -0,0 +1,82 @@
+package atoship
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// logRecorder is a Logger that keeps everything written to it
+type logRecorder struct{ strings.Builder }
+
+func (l *logRecorder) Printf(format string, v ...any) {
+	fmt.Fprintf(&l.Builder, format+"\n", v...)
+}
+
+func TestRedactorURL(t *testing.T) {
+	r := &redactor{apiKey: "test_secret", fields: map[string]bool{"search": true, "q": true}}
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://api.atoship.com/api/orders", "https://api.atoship.com/api/orders"},
+		{"https://api.atoship.com/api/orders?page=2&search=jane%40example.com&limit=10", "https://api.atoship.com/api/orders?page=2&search=[REDACTED]&limit=10"},
+		{"https://api.atoship.com/api/address-search?q=123+Main+St&country=US", "https://api.atoship.com/api/address-search?q=[REDACTED]&country=US"},
+		{"https://api.atoship.com/api/orders?Search=Jane", "https://api.atoship.com/api/orders?Search=[REDACTED]"},
+		{"https://api.atoship.com/api/orders?status=pending&key=test_secret", "https://api.atoship.com/api/orders?status=pending&key=[REDACTED]"},
+	}
+	for _, tt := range tests {
+		u, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := r.url(u); got != tt.want {
+			t.Errorf("url(%s) = %s, want %s", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestDebugLogRedactsPersonalData(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"data":{"orders":[{"id":"ord_1","recipientName":"Jane Doe","recipientEmail":"jane@example.com"}],"total":1}}`)
+	}))
+	defer srv.Close()
+
+	var log logRecorder
+	client := NewClient("test_secret_key", WithBaseURL(srv.URL), WithDebugLogger(&log))
+	_, err := client.Orders.List(context.Background(), &ListOrdersOptions{Search: "jane@example.com", Status: "pending"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := log.String()
+	for _, leaked := range []string{"jane", "Jane", "test_secret_key"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("debug log contains %q:\n%s", leaked, out)
+		}
+	}
+	for _, kept := range []string{"search=[REDACTED]", "status=pending", `"id":"ord_1"`} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("debug log is missing %q:\n%s", kept, out)
+		}
+	}
+}
+
+func TestDebugLogRedactsFailedRequestURL(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	var log logRecorder
+	client := NewClient("test_secret_key", WithBaseURL(srv.URL), WithDebugLogger(&log), WithRetryPolicy(RetryPolicy{}))
+	if _, err := client.Addresses.Search(context.Background(), "123 Main St", "US"); err == nil {
+		t.Fatal("request to a closed server succeeded")
+	}
+	if out := log.String(); strings.Contains(out, "Main") || !strings.Contains(out, "q=[REDACTED]") {
+		t.Errorf("debug log contains the search query:\n%s", out)
+	}
+}
@@ -0,0 +1,97 @@
+package atoship
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Environment is the atoship environment a client talks to
+type Environment string
+
+// Environments
+const (
+	// EnvironmentSandbox creates test objects and never charges for labels
+	EnvironmentSandbox Environment = "sandbox"
+	// EnvironmentLive creates real shipments and charges for labels
+	EnvironmentLive Environment = "live"
+)
+
+// ErrLiveModeRequired is wrapped by the configuration error returned when
+// the live guard refuses a call that spends money
+var ErrLiveModeRequired = errors.New("atoship: live mode required")
+
+// WithEnvironment sets the client's environment explicitly instead of
+// deriving it from the API key. Only a client created with
+// WithEnvironment(EnvironmentLive) passes the live guard, and a client set
+// to EnvironmentSandbox refuses to spend money with a live key even when
+// the guard is off.
+func WithEnvironment(env Environment) ClientOption {
+	return func(c *Client) {
+		c.environment = env
+		c.environmentSet = true
+	}
+}
+
+// WithLiveGuard refuses calls that spend money, such as
+// ShippingService.PurchaseLabel and OrdersService.BulkCreate, unless the
+// client uses a sandbox key or was created with
+// WithEnvironment(EnvironmentLive). Refused calls return an error matching
+// ErrConfig and ErrLiveModeRequired without sending a request.
+func WithLiveGuard(enabled bool) ClientOption {
+	return func(c *Client) {
+		c.liveGuard = enabled
+	}
+}
+
+// Environment returns the environment set with WithEnvironment, or the one
+// implied by the API key prefix. Keys that are not recognizably sandbox
+// keys are treated as live.
+func (c *Client) Environment() Environment {
+	return c.environment
+}
+
+// environmentForKey derives the environment from an API key prefix such as
+// "test_" or "sk_test_"
+func environmentForKey(apiKey string) Environment {
+	if strings.HasPrefix(apiKey, "test_") || strings.Contains(apiKey, "_test_") {
+		return EnvironmentSandbox
+	}
+	return EnvironmentLive
+}
+
+// parseEnvironment parses an environment name
+func parseEnvironment(s string) (Environment, error) {
+	switch Environment(strings.ToLower(s)) {
+	case EnvironmentSandbox, "test":
+		return EnvironmentSandbox, nil
+	case EnvironmentLive, "production":
+		return EnvironmentLive, nil
+	}
+	return "", fmt.Errorf("unknown environment %q", s)
+}
+
+// requireLive enforces the live guard before operation spends money.
+// Sandbox keys cannot spend money and always pass. A live key on a client
+// set to EnvironmentSandbox is refused whether or not the guard is on.
+func (c *Client) requireLive(operation string) error {
+	if environmentForKey(c.apiKey) == EnvironmentSandbox {
+		return nil
+	}
+	if c.environmentSet && c.environment == EnvironmentLive {
+		return nil
+	}
+	if !c.liveGuard && !c.environmentSet {
+		return nil
+	}
+
+	reason := "the client was not created with WithEnvironment(EnvironmentLive)"
+	if c.environmentSet {
+		reason = fmt.Sprintf("the client is in %s mode", c.environment)
+	}
+	return &APIError{
+		Code:    ErrCodeConfigError,
+		Message: fmt.Sprintf("%s refused: the API key is a live key but %s", operation, reason),
+		Err:     ErrLiveModeRequired,
+	}
+}
@@ -0,0 +1,38 @@
+package atoship
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRequireLive(t *testing.T) {
+	tests := []struct {
+		name    string
+		apiKey  string
+		opts    []ClientOption
+		refused bool
+	}{
+		{name: "sandbox key", apiKey: "test_abc"},
+		{name: "sandbox key with guard", apiKey: "sk_test_abc", opts: []ClientOption{WithLiveGuard(true)}},
+		{name: "live key without guard", apiKey: "live_abc"},
+		{name: "live key with guard", apiKey: "live_abc", opts: []ClientOption{WithLiveGuard(true)}, refused: true},
+		{name: "live key with guard in live mode", apiKey: "live_abc", opts: []ClientOption{WithLiveGuard(true), WithEnvironment(EnvironmentLive)}},
+		{name: "live key in live mode", apiKey: "live_abc", opts: []ClientOption{WithEnvironment(EnvironmentLive)}},
+		{name: "live key in sandbox mode", apiKey: "live_abc", opts: []ClientOption{WithEnvironment(EnvironmentSandbox)}, refused: true},
+		{name: "live key in sandbox mode with guard off", apiKey: "live_abc", opts: []ClientOption{WithEnvironment(EnvironmentSandbox), WithLiveGuard(false)}, refused: true},
+		{name: "live key in sandbox mode with guard", apiKey: "live_abc", opts: []ClientOption{WithEnvironment(EnvironmentSandbox), WithLiveGuard(true)}, refused: true},
+		{name: "sandbox key in sandbox mode", apiKey: "test_abc", opts: []ClientOption{WithEnvironment(EnvironmentSandbox)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewClient(tt.apiKey, tt.opts...).requireLive("PurchaseLabel")
+			if tt.refused {
+				if !errors.Is(err, ErrLiveModeRequired) || !errors.Is(err, ErrConfig) {
+					t.Errorf("err = %v, want ErrLiveModeRequired and ErrConfig", err)
+				}
+			} else if err != nil {
+				t.Errorf("err = %v, want nil", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package atoship
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+)
+
+// Common error codes
+const (
+	ErrCodeValidation     = "VALIDATION_ERROR"
+	ErrCodeAuthentication = "AUTHENTICATION_ERROR"
+	ErrCodeAuthorization  = "AUTHORIZATION_ERROR"
+	ErrCodeNotFound       = "NOT_FOUND"
+	ErrCodeRateLimit      = "RATE_LIMIT_EXCEEDED"
+	ErrCodeServerError    = "SERVER_ERROR"
+	ErrCodeNetworkError   = "NETWORK_ERROR"
+	ErrCodeTimeoutError   = "TIMEOUT_ERROR"
+	ErrCodeConfigError    = "CONFIGURATION_ERROR"
+	ErrCodeAPIError       = "API_ERROR"
+)
+
+// Sentinel errors matched by APIError through errors.Is
+var (
+	ErrValidation   = errors.New("atoship: validation failed")
+	ErrUnauthorized = errors.New("atoship: unauthorized")
+	ErrForbidden    = errors.New("atoship: forbidden")
+	ErrNotFound     = errors.New("atoship: not found")
+	ErrRateLimited  = errors.New("atoship: rate limited")
+	ErrServer       = errors.New("atoship: server error")
+	ErrNetwork      = errors.New("atoship: network error")
+	ErrTimeout      = errors.New("atoship: request timed out")
+	ErrConfig       = errors.New("atoship: configuration error")
+)
+
+// APIError represents an API error
+type APIError struct {
+	Code       string `json:"code"`
+	Message    string `json:"message"`
+	StatusCode int    `json:"statusCode"`
+	RequestID  string `json:"requestId"`
+	Details    any    `json:"details,omitempty"`
+	// Attempts is the number of times the request was sent
+	Attempts int `json:"-"`
+	// Err is the underlying transport error, if any
+	Err error `json:"-"`
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	return fmt.Sprintf("atoship API error: %s (code: %s, status: %d)", e.Message, e.Code, e.StatusCode)
+}
+
+// Unwrap returns the underlying transport error
+func (e *APIError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether the error matches one of the sentinel errors, using the
+// error code and falling back to the HTTP status
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrValidation:
+		return e.Code == ErrCodeValidation || e.StatusCode == http.StatusBadRequest || e.StatusCode == http.StatusUnprocessableEntity
+	case ErrUnauthorized:
+		return e.Code == ErrCodeAuthentication || e.StatusCode == http.StatusUnauthorized
+	case ErrForbidden:
+		return e.Code == ErrCodeAuthorization || e.StatusCode == http.StatusForbidden
+	case ErrNotFound:
+		return e.Code == ErrCodeNotFound || e.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return e.Code == ErrCodeRateLimit || e.StatusCode == http.StatusTooManyRequests
+	case ErrServer:
+		return e.Code == ErrCodeServerError || e.StatusCode >= http.StatusInternalServerError
+	case ErrNetwork:
+		return e.Code == ErrCodeNetworkError
+	case ErrTimeout:
+		return e.Code == ErrCodeTimeoutError
+	case ErrConfig:
+		return e.Code == ErrCodeConfigError
+	}
+	return false
+}
+
+// FieldError describes a validation failure on a single request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+}
+
+// FieldErrors returns the field-level validation failures carried in
+// Details. The API reports them either as a list of objects or as a map of
+// field name to message(s).
+func (e *APIError) FieldErrors() []FieldError {
+	if e.Details == nil {
+		return nil
+	}
+	raw, err := json.Marshal(e.Details)
+	if err != nil {
+		return nil
+	}
+
+	var wrapped struct {
+		Fields []FieldError `json:"fields"`
+		Errors []FieldError `json:"errors"`
+	}
+	if err := json.Unmarshal(raw, &wrapped); err == nil {
+		if len(wrapped.Fields) > 0 {
+			return wrapped.Fields
+		}
+		if len(wrapped.Errors) > 0 {
+			return wrapped.Errors
+		}
+	}
+
+	var list []FieldError
+	if err := json.Unmarshal(raw, &list); err == nil {
+		return list
+	}
+
+	var byField map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &byField); err != nil {
+		return nil
+	}
+	fields := make([]string, 0, len(byField))
+	for field := range byField {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	var out []FieldError
+	for _, field := range fields {
+		var msg string
+		var msgs []string
+		switch {
+		case json.Unmarshal(byField[field], &msg) == nil:
+			out = append(out, FieldError{Field: field, Message: msg})
+		case json.Unmarshal(byField[field], &msgs) == nil:
+			for _, m := range msgs {
+				out = append(out, FieldError{Field: field, Message: m})
+			}
+		}
+	}
+	return out
+}
+
+// DecodeError is returned when a response cannot be decoded
+type DecodeError struct {
+	StatusCode int
+	RequestID  string
+	Body       []byte
+	Err        error
+}
+
+// Error implements the error interface
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("atoship: decoding response (status: %d): %v", e.StatusCode, e.Err)
+}
+
+// Unwrap returns the underlying decoding error
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// transportError wraps an error raised before a response was received
+func transportError(err error) *APIError {
+	code := ErrCodeNetworkError
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		code = ErrCodeTimeoutError
+	}
+	return &APIError{
+		Code:    code,
+		Message: err.Error(),
+		Err:     err,
+	}
+}
+
+// isTransport reports whether the error was raised before a response was
+// received
+func (e *APIError) isTransport() bool {
+	return e.Err != nil && e.StatusCode == 0
+}
+
+// codeForStatus maps an HTTP status to an error code
+func codeForStatus(status int) string {
+	switch {
+	case status == http.StatusBadRequest, status == http.StatusUnprocessableEntity:
+		return ErrCodeValidation
+	case status == http.StatusUnauthorized:
+		return ErrCodeAuthentication
+	case status == http.StatusForbidden:
+		return ErrCodeAuthorization
+	case status == http.StatusNotFound:
+		return ErrCodeNotFound
+	case status == http.StatusTooManyRequests:
+		return ErrCodeRateLimit
+	case status >= http.StatusInternalServerError:
+		return ErrCodeServerError
+	}
+	return ErrCodeAPIError
+}
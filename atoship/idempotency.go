@@ -0,0 +1,31 @@
+package atoship
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// headerIdempotencyKey marks a request as safe to replay
+const headerIdempotencyKey = "Idempotency-Key"
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx that pins the Idempotency-Key sent
+// with mutating calls made using it. Callers typically derive the key from
+// their own order number so a resubmitted purchase is recognized by the API.
+//
+// The key applies to every POST or PATCH made with the returned context, so
+// derive a fresh context for each logical operation.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// idempotencyKey returns the key for a mutating call: the one pinned on ctx,
+// or a newly generated one. Both are reused across retries of the call.
+func idempotencyKey(ctx context.Context) string {
+	if key, ok := ctx.Value(idempotencyKeyContextKey{}).(string); ok && key != "" {
+		return key
+	}
+	return uuid.NewString()
+}
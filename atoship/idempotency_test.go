@@ -0,0 +1,65 @@
+package atoship
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPurchaseLabelRetriedWithSameKey(t *testing.T) {
+	var keys []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(headerIdempotencyKey))
+		w.Header().Set("Content-Type", "application/json")
+		if len(keys) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"code":"SERVER_ERROR","message":"try again"}`)
+			return
+		}
+		fmt.Fprint(w, `{"success":true,"data":{"id":"lbl_1"}}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient("k", WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond}))
+	label, err := client.Shipping.PurchaseLabel(context.Background(), &PurchaseLabelRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if label.ID != "lbl_1" {
+		t.Errorf("label ID = %q", label.ID)
+	}
+	if len(keys) != 3 {
+		t.Fatalf("sent %d attempts, want 3", len(keys))
+	}
+	if keys[0] == "" || keys[1] != keys[0] || keys[2] != keys[0] {
+		t.Errorf("idempotency keys = %q, want one key reused", keys)
+	}
+}
+
+func TestWithIdempotencyKey(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.Header.Get(headerIdempotencyKey)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"data":{"id":"o1"}}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient("k", WithBaseURL(srv.URL))
+	ctx := WithIdempotencyKey(context.Background(), "order-42")
+	if _, err := client.Orders.Create(ctx, &CreateOrderRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if got != "order-42" {
+		t.Errorf("Idempotency-Key = %q, want order-42", got)
+	}
+	if _, err := client.Orders.Get(ctx, "o1"); err != nil {
+		t.Fatal(err)
+	}
+	if got != "" {
+		t.Errorf("GET sent Idempotency-Key %q", got)
+	}
+}
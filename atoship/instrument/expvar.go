@@ -0,0 +1,175 @@
+// Package instrument provides atoship.Instrumentation implementations that
+// depend only on the standard library: Expvar publishes per-operation
+// metrics, and TraceContext propagates W3C trace context headers.
+//
+//	metrics := instrument.NewExpvar("atoship", nil)
+//	client := atoship.NewClient(apiKey,
+//		atoship.WithInstrumentation(metrics, instrument.TraceContext{}),
+//	)
+package instrument
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+// DefaultBuckets are the upper bounds of the latency histogram buckets used
+// when NewExpvar is given none
+var DefaultBuckets = []time.Duration{
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+	10 * time.Second,
+}
+
+// Expvar records per-operation call counts, errors by code, HTTP statuses,
+// bytes sent and received, and a latency histogram. It implements
+// expvar.Var, rendering as a JSON object keyed by operation name:
+//
+//	{"orders.create": {"calls": 3, "errors": 1,
+//	  "error_codes": {"VALIDATION_ERROR": 1}, "status": {"201": 2, "422": 1},
+//	  "request_bytes": 1830, "response_bytes": 2411,
+//	  "latency_ms": {"buckets": {"25": 0, "50": 2, ..., "+Inf": 3},
+//	    "count": 3, "sum": 131.5}}}
+//
+// Histogram buckets are cumulative: each counts the calls that took at
+// most its bound in milliseconds.
+type Expvar struct {
+	buckets []time.Duration
+
+	mu  sync.Mutex
+	ops map[string]*operationStats
+}
+
+type operationStats struct {
+	Calls         int64            `json:"calls"`
+	Errors        int64            `json:"errors"`
+	ErrorCodes    map[string]int64 `json:"error_codes"`
+	Status        map[string]int64 `json:"status"`
+	RequestBytes  int64            `json:"request_bytes"`
+	ResponseBytes int64            `json:"response_bytes"`
+	Latency       histogram        `json:"-"`
+}
+
+type histogram struct {
+	counts []int64
+	count  int64
+	sum    time.Duration
+}
+
+// NewExpvar creates an Expvar with the given latency histogram bucket
+// bounds, or DefaultBuckets if none, and publishes it under name. An empty
+// name skips publishing. Like expvar.Publish, it panics if name is
+// already in use.
+func NewExpvar(name string, buckets []time.Duration) *Expvar {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	e := &Expvar{
+		buckets: append([]time.Duration(nil), buckets...),
+		ops:     map[string]*operationStats{},
+	}
+	if name != "" {
+		expvar.Publish(name, e)
+	}
+	return e
+}
+
+// OnRequestStart implements atoship.Instrumentation
+func (e *Expvar) OnRequestStart(ctx context.Context, info *atoship.RequestInfo) context.Context {
+	return ctx
+}
+
+// OnRequestEnd implements atoship.Instrumentation
+func (e *Expvar) OnRequestEnd(ctx context.Context, info *atoship.RequestInfo, result *atoship.RequestResult) {
+	name := info.Operation
+	if name == "" {
+		name = info.Method + " " + info.Path
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	op := e.ops[name]
+	if op == nil {
+		op = &operationStats{
+			ErrorCodes: map[string]int64{},
+			Status:     map[string]int64{},
+			Latency:    histogram{counts: make([]int64, len(e.buckets))},
+		}
+		e.ops[name] = op
+	}
+
+	op.Calls++
+	if result.Err != nil {
+		op.Errors++
+		if result.ErrorCode != "" {
+			op.ErrorCodes[result.ErrorCode]++
+		}
+	}
+	if result.StatusCode != 0 {
+		op.Status[strconv.Itoa(result.StatusCode)]++
+	}
+	op.RequestBytes += result.RequestBytes
+	op.ResponseBytes += result.ResponseBytes
+
+	op.Latency.count++
+	op.Latency.sum += result.Duration
+	for i, bound := range e.buckets {
+		if result.Duration <= bound {
+			op.Latency.counts[i]++
+		}
+	}
+}
+
+// String implements expvar.Var
+func (e *Expvar) String() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	type jsonHistogram struct {
+		Buckets map[string]int64 `json:"buckets"`
+		Count   int64            `json:"count"`
+		Sum     float64          `json:"sum"`
+	}
+	type jsonStats struct {
+		*operationStats
+		Latency jsonHistogram `json:"latency_ms"`
+	}
+
+	out := make(map[string]jsonStats, len(e.ops))
+	for name, op := range e.ops {
+		buckets := make(map[string]int64, len(e.buckets)+1)
+		for i, bound := range e.buckets {
+			buckets[strconv.FormatFloat(milliseconds(bound), 'f', -1, 64)] = op.Latency.counts[i]
+		}
+		buckets["+Inf"] = op.Latency.count
+		out[name] = jsonStats{
+			operationStats: op,
+			Latency: jsonHistogram{
+				Buckets: buckets,
+				Count:   op.Latency.count,
+				Sum:     milliseconds(op.Latency.sum),
+			},
+		}
+	}
+	b, err := json.Marshal(out)
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
@@ -0,0 +1,178 @@
+package instrument
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+	"github.com/atoship-LLC/atoship-go/atoship/atoshiptest"
+)
+
+// end records one call with the given duration and result
+func end(e *Expvar, operation string, d time.Duration, result atoship.RequestResult) {
+	result.Duration = d
+	e.OnRequestEnd(context.Background(), &atoship.RequestInfo{Method: "GET", Path: "/api/orders", Operation: operation}, &result)
+}
+
+func TestExpvarHistogramBuckets(t *testing.T) {
+	e := NewExpvar("", []time.Duration{2500 * time.Microsecond, 10 * time.Millisecond, 100 * time.Millisecond})
+	for _, d := range []time.Duration{
+		time.Millisecond,
+		2500 * time.Microsecond,                 // on a bound, so counted in it
+		2500*time.Microsecond + time.Nanosecond, // just over a bound
+		10 * time.Millisecond,
+		100 * time.Millisecond,
+		time.Second, // only in +Inf
+	} {
+		end(e, "orders.list", d, atoship.RequestResult{StatusCode: 200})
+	}
+
+	var got map[string]struct {
+		Latency struct {
+			Buckets map[string]int64 `json:"buckets"`
+			Count   int64            `json:"count"`
+			Sum     float64          `json:"sum"`
+		} `json:"latency_ms"`
+	}
+	if err := json.Unmarshal([]byte(e.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	latency := got["orders.list"].Latency
+	want := map[string]int64{"2.5": 2, "10": 4, "100": 5, "+Inf": 6}
+	if !reflect.DeepEqual(latency.Buckets, want) {
+		t.Errorf("buckets = %v, want %v", latency.Buckets, want)
+	}
+	if latency.Count != 6 {
+		t.Errorf("count = %d, want 6", latency.Count)
+	}
+	if wantSum := 1116.000001; latency.Sum != wantSum {
+		t.Errorf("sum = %v, want %v", latency.Sum, wantSum)
+	}
+}
+
+func TestExpvarDefaultBuckets(t *testing.T) {
+	e := NewExpvar("", nil)
+	end(e, "orders.list", 30*time.Millisecond, atoship.RequestResult{})
+
+	var got map[string]struct {
+		Latency struct {
+			Buckets map[string]int64 `json:"buckets"`
+		} `json:"latency_ms"`
+	}
+	if err := json.Unmarshal([]byte(e.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	buckets := got["orders.list"].Latency.Buckets
+	if len(buckets) != len(DefaultBuckets)+1 || buckets["25"] != 0 || buckets["50"] != 1 || buckets["2500"] != 1 || buckets["+Inf"] != 1 {
+		t.Errorf("buckets = %v", buckets)
+	}
+}
+
+func TestExpvarJSON(t *testing.T) {
+	const name = "atoship_instrument_test"
+	e := NewExpvar(name, nil)
+	if expvar.Get(name) != e {
+		t.Fatalf("expvar.Get(%q) = %v, want the published Expvar", name, expvar.Get(name))
+	}
+	if got := e.String(); got != "{}" {
+		t.Errorf("String before any call = %s, want {}", got)
+	}
+
+	end(e, "orders.create", 20*time.Millisecond, atoship.RequestResult{StatusCode: 201, RequestBytes: 300, ResponseBytes: 500})
+	end(e, "orders.create", 40*time.Millisecond, atoship.RequestResult{
+		StatusCode:    422,
+		ErrorCode:     atoship.ErrCodeValidation,
+		Err:           &atoship.APIError{Code: atoship.ErrCodeValidation},
+		RequestBytes:  200,
+		ResponseBytes: 100,
+	})
+	end(e, "", time.Second, atoship.RequestResult{Err: context.DeadlineExceeded})
+
+	var got map[string]map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(expvar.Get(name).String()), &got); err != nil {
+		t.Fatalf("published value is not JSON: %v", err)
+	}
+	var names []string
+	for name := range got {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if want := []string{"GET /api/orders", "orders.create"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("operations = %q, want %q", names, want)
+	}
+
+	create := got["orders.create"]
+	var keys []string
+	for key := range create {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	wantKeys := []string{"calls", "error_codes", "errors", "latency_ms", "request_bytes", "response_bytes", "status"}
+	if !reflect.DeepEqual(keys, wantKeys) {
+		t.Errorf("keys = %q, want %q", keys, wantKeys)
+	}
+	for key, want := range map[string]string{
+		"calls":          `2`,
+		"errors":         `1`,
+		"error_codes":    `{"VALIDATION_ERROR":1}`,
+		"status":         `{"201":1,"422":1}`,
+		"request_bytes":  `500`,
+		"response_bytes": `600`,
+	} {
+		if string(create[key]) != want {
+			t.Errorf("%s = %s, want %s", key, create[key], want)
+		}
+	}
+
+	// A failure without a response counts as an error with no status or
+	// error code
+	unnamed := got["GET /api/orders"]
+	if string(unnamed["errors"]) != `1` || string(unnamed["status"]) != `{}` || string(unnamed["error_codes"]) != `{}` {
+		t.Errorf("unnamed operation = %s, %s, %s", unnamed["errors"], unnamed["status"], unnamed["error_codes"])
+	}
+}
+
+func TestExpvarWithClient(t *testing.T) {
+	e := NewExpvar("", nil)
+	client, srv := atoshiptest.NewClient(atoship.WithInstrumentation(e))
+	defer srv.Close()
+
+	ctx := context.Background()
+	if _, err := client.Orders.Get(ctx, "ord_missing"); err == nil {
+		t.Fatal("Get of a missing order succeeded")
+	}
+	if _, err := client.Carriers.List(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]struct {
+		Calls      int64            `json:"calls"`
+		Errors     int64            `json:"errors"`
+		ErrorCodes map[string]int64 `json:"error_codes"`
+		Status     map[string]int64 `json:"status"`
+	}
+	if err := json.Unmarshal([]byte(e.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Errorf("operations = %v, want 2", got)
+	}
+	var errors int64
+	for name, op := range got {
+		if op.Calls != 1 {
+			t.Errorf("%s: calls = %d, want 1", name, op.Calls)
+		}
+		errors += op.Errors
+		if op.Errors == 1 && (op.Status["404"] != 1 || op.ErrorCodes[atoship.ErrCodeNotFound] != 1) {
+			t.Errorf("%s: failed call recorded as %+v", name, op)
+		}
+	}
+	if errors != 1 {
+		t.Errorf("errors = %d, want 1", errors)
+	}
+}
@@ -0,0 +1,107 @@
+package instrument
+
+import (
+	"context"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+// W3C trace context headers
+const (
+	TraceParentHeader = "traceparent"
+	TraceStateHeader  = "tracestate"
+)
+
+// TraceContext propagates W3C trace context
+// (https://www.w3.org/TR/trace-context/) from the context of each call into
+// its traceparent and tracestate headers, so that atoship requests join the
+// caller's trace. Invalid traceparent values are not sent.
+type TraceContext struct {
+	// Extract returns the trace context for ctx. The traceparent should
+	// identify the span that makes the call. The default returns the
+	// values stored with ContextWithTraceParent.
+	Extract func(ctx context.Context) (traceparent, tracestate string)
+}
+
+type traceContextKey struct{}
+
+type traceContext struct {
+	traceparent string
+	tracestate  string
+}
+
+// ContextWithTraceParent returns a copy of ctx carrying a traceparent and
+// optional tracestate for TraceContext to send, such as the headers of an
+// incoming request:
+//
+//	ctx := instrument.ContextWithTraceParent(r.Context(),
+//		r.Header.Get("traceparent"), r.Header.Get("tracestate"))
+func ContextWithTraceParent(ctx context.Context, traceparent, tracestate string) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, traceContext{traceparent, tracestate})
+}
+
+// TraceParentFromContext returns the values stored with
+// ContextWithTraceParent
+func TraceParentFromContext(ctx context.Context) (traceparent, tracestate string) {
+	tc, _ := ctx.Value(traceContextKey{}).(traceContext)
+	return tc.traceparent, tc.tracestate
+}
+
+// OnRequestStart implements atoship.Instrumentation
+func (t TraceContext) OnRequestStart(ctx context.Context, info *atoship.RequestInfo) context.Context {
+	extract := t.Extract
+	if extract == nil {
+		extract = TraceParentFromContext
+	}
+	traceparent, tracestate := extract(ctx)
+	if !validTraceParent(traceparent) {
+		return ctx
+	}
+	info.Header.Set(TraceParentHeader, traceparent)
+	if tracestate != "" {
+		info.Header.Set(TraceStateHeader, tracestate)
+	}
+	return ctx
+}
+
+// OnRequestEnd implements atoship.Instrumentation
+func (t TraceContext) OnRequestEnd(ctx context.Context, info *atoship.RequestInfo, result *atoship.RequestResult) {
+}
+
+// validTraceParent reports whether s is a well-formed traceparent:
+// version-traceid-parentid-flags in lowercase hex, with a non-zero trace
+// and parent ID. Versions after 00 may append further fields.
+func validTraceParent(s string) bool {
+	if len(s) < 55 || (len(s) > 55 && (s[:2] == "00" || s[55] != '-')) {
+		return false
+	}
+	if s[2] != '-' || s[35] != '-' || s[52] != '-' || s[:2] == "ff" {
+		return false
+	}
+	version, traceID, parentID, flags := s[:2], s[3:35], s[36:52], s[53:55]
+	for _, field := range []string{version, traceID, parentID, flags} {
+		if !isHex(field) {
+			return false
+		}
+	}
+	return !isZero(traceID) && !isZero(parentID)
+}
+
+func isHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+func isZero(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] != '0' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,113 @@
+package instrument
+
+import (
+	"context"
+	"testing"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+	"github.com/atoship-LLC/atoship-go/atoship/atoshiptest"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestValidTraceParent(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want bool
+	}{
+		{"valid", testTraceParent, true},
+		{"not sampled", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-00", true},
+		{"future version", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", true},
+		{"future version with more fields", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01-extra", true},
+		{"empty", "", false},
+		{"invalid version ff", "ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"non-hex version", "0x-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", false},
+		{"version 00 with more fields", testTraceParent + "-extra", false},
+		{"future version without a separator", "01-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01extra", false},
+		{"all-zero trace ID", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", false},
+		{"all-zero parent ID", "00-4bf92f3577b34da6a3ce929d0e0e4736-0000000000000000-01", false},
+		{"short trace ID", "00-4bf92f3577b34da6a3ce929d0e0e473-00f067aa0ba902b7-01", false},
+		{"long parent ID", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7a-01", false},
+		{"short flags", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-1", false},
+		{"uppercase trace ID", "00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01", false},
+		{"uppercase parent ID", "00-4bf92f3577b34da6a3ce929d0e0e4736-00F067AA0BA902B7-01", false},
+		{"wrong separator", "00_4bf92f3577b34da6a3ce929d0e0e4736_00f067aa0ba902b7_01", false},
+		{"non-hex flags", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-0g", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validTraceParent(tt.s); got != tt.want {
+				t.Errorf("validTraceParent(%q) = %v, want %v", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTraceContextInjection(t *testing.T) {
+	const otherTraceParent = "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"
+
+	tests := []struct {
+		name        string
+		tc          TraceContext
+		ctx         context.Context
+		traceparent string
+		tracestate  string
+	}{
+		{
+			name:        "from context",
+			ctx:         ContextWithTraceParent(context.Background(), testTraceParent, "congo=t61rcWkgMzE"),
+			traceparent: testTraceParent,
+			tracestate:  "congo=t61rcWkgMzE",
+		},
+		{
+			name:        "without tracestate",
+			ctx:         ContextWithTraceParent(context.Background(), testTraceParent, ""),
+			traceparent: testTraceParent,
+		},
+		{
+			name: "invalid traceparent",
+			ctx:  ContextWithTraceParent(context.Background(), "00-00000000000000000000000000000000-00f067aa0ba902b7-01", "congo=t61rcWkgMzE"),
+		},
+		{
+			name: "no trace context",
+			ctx:  context.Background(),
+		},
+		{
+			name: "custom extract",
+			tc: TraceContext{Extract: func(context.Context) (string, string) {
+				return otherTraceParent, "rojo=00f067aa0ba902b7"
+			}},
+			ctx:         ContextWithTraceParent(context.Background(), testTraceParent, ""),
+			traceparent: otherTraceParent,
+			tracestate:  "rojo=00f067aa0ba902b7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, srv := atoshiptest.NewClient(atoship.WithInstrumentation(tt.tc))
+			defer srv.Close()
+
+			if _, err := client.Carriers.List(tt.ctx); err != nil {
+				t.Fatal(err)
+			}
+			header := srv.Requests()[0].Header
+			if got := header.Get(TraceParentHeader); got != tt.traceparent {
+				t.Errorf("traceparent = %q, want %q", got, tt.traceparent)
+			}
+			if got := header.Get(TraceStateHeader); got != tt.tracestate {
+				t.Errorf("tracestate = %q, want %q", got, tt.tracestate)
+			}
+		})
+	}
+}
+
+func TestTraceParentFromContext(t *testing.T) {
+	ctx := ContextWithTraceParent(context.Background(), testTraceParent, "congo=t61rcWkgMzE")
+	if traceparent, tracestate := TraceParentFromContext(ctx); traceparent != testTraceParent || tracestate != "congo=t61rcWkgMzE" {
+		t.Errorf("TraceParentFromContext = %q, %q", traceparent, tracestate)
+	}
+	if traceparent, tracestate := TraceParentFromContext(context.Background()); traceparent != "" || tracestate != "" {
+		t.Errorf("TraceParentFromContext of an empty context = %q, %q", traceparent, tracestate)
+	}
+}
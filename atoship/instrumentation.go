@@ -0,0 +1,85 @@
+package atoship
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+)
+
+// Instrumentation observes every API call, for example to record metrics
+// or trace spans. The instrument package has ready-made implementations.
+//
+// OnRequestStart is called once per call, before the first attempt. It may
+// add headers to info.Header, which are sent with every attempt, and may
+// return a derived context, which is used for the call and passed to
+// OnRequestEnd. OnRequestEnd is called once when the call returns, after
+// any retries.
+type Instrumentation interface {
+	OnRequestStart(ctx context.Context, info *RequestInfo) context.Context
+	OnRequestEnd(ctx context.Context, info *RequestInfo, result *RequestResult)
+}
+
+// RequestInfo describes an API call
+type RequestInfo struct {
+	// Operation names the endpoint, such as "orders.create"; empty for
+	// paths the SDK does not recognize
+	Operation string
+	// Method is the HTTP method
+	Method string
+	// Path is the path template, such as "/api/orders/{orderId}"
+	Path string
+	// Header holds headers added to the request
+	Header http.Header
+}
+
+// RequestResult describes the outcome of an API call
+type RequestResult struct {
+	// StatusCode is the HTTP status of the final attempt; zero if no
+	// response was received
+	StatusCode int
+	// ErrorCode is the APIError code of a failed call
+	ErrorCode string
+	// Err is the error returned to the caller
+	Err error
+	// RequestID identifies the call to atoship support
+	RequestID string
+	// Duration is the total time spent on the call, including retries
+	Duration time.Duration
+	// Attempts is the number of times the request was sent
+	Attempts int
+	// RequestBytes is the size of the request body of the final attempt
+	// as sent, after any compression
+	RequestBytes int64
+	// ResponseBytes is the size of the response body of the final attempt
+	// as received, before decompression by WithCompression
+	ResponseBytes int64
+}
+
+// WithInstrumentation adds instrumentation observing every API call.
+// OnRequestStart is called in the order given and OnRequestEnd in reverse.
+func WithInstrumentation(instrumentation ...Instrumentation) ClientOption {
+	return func(c *Client) {
+		c.instrumentation = append(c.instrumentation, instrumentation...)
+	}
+}
+
+// endInstrumentation reports the outcome of a call to the instrumentation
+func (c *Client) endInstrumentation(ctx context.Context, info *RequestInfo, meta *ResponseMeta, sent, received int64, started time.Time, err error) {
+	result := &RequestResult{
+		StatusCode:    meta.StatusCode,
+		Err:           err,
+		RequestID:     meta.RequestID,
+		Duration:      time.Since(started),
+		Attempts:      meta.Attempts,
+		RequestBytes:  sent,
+		ResponseBytes: received,
+	}
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		result.ErrorCode = apiErr.Code
+	}
+	for i := len(c.instrumentation) - 1; i >= 0; i-- {
+		c.instrumentation[i].OnRequestEnd(ctx, info, result)
+	}
+}
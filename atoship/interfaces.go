@@ -0,0 +1,116 @@
+package atoship
+
+import "context"
+
+// OrdersAPI is the method set of OrdersService
+type OrdersAPI interface {
+	Create(ctx context.Context, req *CreateOrderRequest) (*Order, error)
+	Get(ctx context.Context, orderID string) (*Order, error)
+	Update(ctx context.Context, orderID string, req *UpdateOrderRequest) (*Order, error)
+	List(ctx context.Context, opts *ListOrdersOptions) (*OrderListResponse, error)
+	ListPager(opts *ListOrdersOptions) *Pager[Order]
+	ListAll(ctx context.Context, opts *ListOrdersOptions, fn func(*Order) error) error
+	Delete(ctx context.Context, orderID string) error
+	Ship(ctx context.Context, orderID string, trackingNumber string, carrier string) (*Order, error)
+	Cancel(ctx context.Context, orderID string, reason string) (*Order, error)
+	BulkCreate(ctx context.Context, orders []*CreateOrderRequest) (*BulkCreateResponse, error)
+}
+
+// AddressesAPI is the method set of AddressesService
+type AddressesAPI interface {
+	Create(ctx context.Context, address *Address) (*Address, error)
+	Get(ctx context.Context, addressID string) (*Address, error)
+	Update(ctx context.Context, addressID string, address *Address) (*Address, error)
+	List(ctx context.Context) ([]Address, error)
+	ListPager(opts *ListOptions) *Pager[Address]
+	Delete(ctx context.Context, addressID string) error
+	Validate(ctx context.Context, req *ValidateAddressRequest) (*ValidateAddressResponse, error)
+	Search(ctx context.Context, query string, country string) ([]Address, error)
+}
+
+// ShippingAPI is the method set of ShippingService
+type ShippingAPI interface {
+	GetRates(ctx context.Context, req *RateRequest) ([]ShippingRate, error)
+	PurchaseLabel(ctx context.Context, req *PurchaseLabelRequest) (*ShippingLabel, error)
+	GetLabel(ctx context.Context, labelID string) (*ShippingLabel, error)
+	CancelLabel(ctx context.Context, labelID string) (*ShippingLabel, error)
+}
+
+// TrackingAPI is the method set of TrackingService
+type TrackingAPI interface {
+	Track(ctx context.Context, trackingNumber string) (*TrackingInfo, error)
+	TrackWithCarrier(ctx context.Context, trackingNumber, carrier string) (*TrackingInfo, error)
+	BatchTrack(ctx context.Context, trackingNumbers []string) ([]TrackingInfo, error)
+}
+
+// UsersAPI is the method set of UsersService
+type UsersAPI interface {
+	GetProfile(ctx context.Context) (*User, error)
+	UpdateProfile(ctx context.Context, updates *User) (*User, error)
+}
+
+// AdminAPI is the method set of AdminService
+type AdminAPI interface {
+	GetStats(ctx context.Context) (*Stats, error)
+}
+
+// CarriersAPI is the method set of CarriersService
+type CarriersAPI interface {
+	List(ctx context.Context) ([]Carrier, error)
+	ListPager(opts *ListOptions) *Pager[Carrier]
+}
+
+// WebhooksAPI is the method set of WebhooksService
+type WebhooksAPI interface {
+	Create(ctx context.Context, req *CreateWebhookRequest) (*Webhook, error)
+	List(ctx context.Context) ([]Webhook, error)
+	ListPager(opts *ListOptions) *Pager[Webhook]
+	Get(ctx context.Context, webhookID string) (*Webhook, error)
+	Update(ctx context.Context, webhookID string, req *UpdateWebhookRequest) (*Webhook, error)
+	SetActive(ctx context.Context, webhookID string, active bool) (*Webhook, error)
+	RotateSecret(ctx context.Context, webhookID string) (string, error)
+	SendTest(ctx context.Context, webhookID string, eventType string) (*WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, webhookID string) ([]WebhookDelivery, error)
+	ListDeliveriesPager(webhookID string, opts *ListOptions) *Pager[WebhookDelivery]
+	RetryDelivery(ctx context.Context, webhookID, deliveryID string) (*WebhookDelivery, error)
+	Delete(ctx context.Context, webhookID string) error
+}
+
+// API groups the services of a Client behind interfaces, so code that
+// depends on the SDK can accept fakes in tests. Client.API returns the
+// real implementation.
+type API struct {
+	Orders    OrdersAPI
+	Addresses AddressesAPI
+	Shipping  ShippingAPI
+	Tracking  TrackingAPI
+	Users     UsersAPI
+	Admin     AdminAPI
+	Carriers  CarriersAPI
+	Webhooks  WebhooksAPI
+}
+
+// API returns the client's services as interfaces
+func (c *Client) API() *API {
+	return &API{
+		Orders:    c.Orders,
+		Addresses: c.Addresses,
+		Shipping:  c.Shipping,
+		Tracking:  c.Tracking,
+		Users:     c.Users,
+		Admin:     c.Admin,
+		Carriers:  c.Carriers,
+		Webhooks:  c.Webhooks,
+	}
+}
+
+var (
+	_ OrdersAPI    = (*OrdersService)(nil)
+	_ AddressesAPI = (*AddressesService)(nil)
+	_ ShippingAPI  = (*ShippingService)(nil)
+	_ TrackingAPI  = (*TrackingService)(nil)
+	_ UsersAPI     = (*UsersService)(nil)
+	_ AdminAPI     = (*AdminService)(nil)
+	_ CarriersAPI  = (*CarriersService)(nil)
+	_ WebhooksAPI  = (*WebhooksService)(nil)
+)
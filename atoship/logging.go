@@ -0,0 +1,69 @@
+package atoship
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// Default levels of the records written by WithLogger
+const (
+	DefaultSuccessLogLevel = slog.LevelInfo
+	DefaultFailureLogLevel = slog.LevelWarn
+)
+
+// WithLogger writes one structured record per API call to logger, after
+// any retries. Each record carries the method, path template, operation
+// name, HTTP status, request ID, duration, number of attempts and, for
+// failed calls, the error code and message. Request and response bodies
+// are never logged.
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithLogLevels sets the levels of the records written by WithLogger for
+// calls that succeed and fail. The defaults are DefaultSuccessLogLevel and
+// DefaultFailureLogLevel.
+func WithLogLevels(success, failure slog.Level) ClientOption {
+	return func(c *Client) {
+		c.logLevels = logLevels{success: success, failure: failure}
+	}
+}
+
+// logLevels holds the levels set with WithLogLevels
+type logLevels struct {
+	success slog.Level
+	failure slog.Level
+}
+
+// logCall writes the record for a finished API call
+func (c *Client) logCall(ctx context.Context, info *RequestInfo, started time.Time, meta *ResponseMeta, err error) {
+	level := c.logLevels.success
+	if err != nil {
+		level = c.logLevels.failure
+	}
+	if !c.logger.Enabled(ctx, level) {
+		return
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", info.Method),
+		slog.String("path", info.Path),
+		slog.String("operation", info.Operation),
+		slog.Int("status", meta.StatusCode),
+		slog.String("request_id", meta.RequestID),
+		slog.Duration("duration", time.Since(started)),
+		slog.Int("attempt", meta.Attempts),
+	}
+	if err != nil {
+		var apiErr *APIError
+		if errors.As(err, &apiErr) {
+			attrs = append(attrs, slog.String("error_code", apiErr.Code))
+		}
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	c.logger.LogAttrs(ctx, level, "atoship API call", attrs...)
+}
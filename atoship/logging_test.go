@@ -0,0 +1,207 @@
+package atoship
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// captureHandler is a slog.Handler keeping the records it is given
+type captureHandler struct {
+	level slog.Level
+
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *captureHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *captureHandler) Handle(_ context.Context, r slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, r.Clone())
+	return nil
+}
+
+func (h *captureHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *captureHandler) WithGroup(string) slog.Handler      { return h }
+
+// take returns the captured records and forgets them
+func (h *captureHandler) take() []slog.Record {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	records := h.records
+	h.records = nil
+	return records
+}
+
+// recordAttrs returns the attributes of a record by key
+func recordAttrs(r slog.Record) map[string]slog.Value {
+	attrs := map[string]slog.Value{}
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value
+		return true
+	})
+	return attrs
+}
+
+// newLoggingServer serves an order, a missing order, and carriers that
+// fail once before succeeding
+func newLoggingServer(t *testing.T) *httptest.Server {
+	var carrierCalls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/api/orders/ord_1":
+			w.Header().Set("X-Request-Id", "req_ok")
+			fmt.Fprint(w, `{"success":true,"data":{"id":"ord_1"}}`)
+		case "/api/carriers":
+			w.Header().Set("X-Request-Id", "req_retried")
+			if carrierCalls.Add(1) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				fmt.Fprint(w, `{"success":false,"error":{"code":"SERVER_ERROR","message":"try again"}}`)
+				return
+			}
+			fmt.Fprint(w, `{"success":true,"data":[]}`)
+		default:
+			w.Header().Set("X-Request-Id", "req_missing")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"success":false,"error":{"code":"NOT_FOUND_ERROR","message":"order not found"}}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestLogCall(t *testing.T) {
+	srv := newLoggingServer(t)
+	h := &captureHandler{level: slog.LevelDebug}
+	client := NewClient("test_key",
+		WithBaseURL(srv.URL),
+		WithLogger(slog.New(h)),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 2, InitialBackoff: time.Millisecond}),
+	)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		call    func() error
+		level   slog.Level
+		attrs   map[string]any
+		failure bool
+	}{
+		{
+			name:  "success",
+			call:  func() error { _, err := client.Orders.Get(ctx, "ord_1"); return err },
+			level: DefaultSuccessLogLevel,
+			attrs: map[string]any{
+				"method": "GET", "path": "/api/orders/{orderId}", "operation": "orders.get",
+				"status": int64(200), "request_id": "req_ok", "attempt": int64(1),
+			},
+		},
+		{
+			name:  "success after a retry",
+			call:  func() error { _, err := client.Carriers.List(ctx); return err },
+			level: DefaultSuccessLogLevel,
+			attrs: map[string]any{"operation": "carriers.list", "status": int64(200), "request_id": "req_retried", "attempt": int64(2)},
+		},
+		{
+			name:  "failure",
+			call:  func() error { _, err := client.Orders.Get(ctx, "ord_missing"); return err },
+			level: DefaultFailureLogLevel,
+			attrs: map[string]any{
+				"operation": "orders.get", "status": int64(404), "request_id": "req_missing", "attempt": int64(1),
+				"error_code": ErrCodeNotFound,
+			},
+			failure: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			err := tt.call()
+			elapsed := time.Since(start)
+			if (err != nil) != tt.failure {
+				t.Fatalf("err = %v", err)
+			}
+
+			records := h.take()
+			if len(records) != 1 {
+				t.Fatalf("logged %d records, want 1 per call", len(records))
+			}
+			r := records[0]
+			if r.Level != tt.level || r.Message != "atoship API call" {
+				t.Errorf("record = %v %q, want level %v", r.Level, r.Message, tt.level)
+			}
+			attrs := recordAttrs(r)
+			for key, want := range tt.attrs {
+				if got := attrs[key].Any(); got != want {
+					t.Errorf("%s = %v (%T), want %v", key, got, got, want)
+				}
+			}
+			if d := attrs["duration"].Duration(); d <= 0 || d > elapsed {
+				t.Errorf("duration = %v, want between 0 and %v", d, elapsed)
+			}
+			if _, ok := attrs["error"]; ok != tt.failure {
+				t.Errorf("error attribute present = %v, want %v", ok, tt.failure)
+			}
+			if _, ok := attrs["error_code"]; ok != tt.failure {
+				t.Errorf("error_code attribute present = %v, want %v", ok, tt.failure)
+			}
+		})
+	}
+}
+
+func TestWithLogLevels(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         []ClientOption
+		handlerLevel slog.Level
+		success      []slog.Level
+		failure      []slog.Level
+	}{
+		{"defaults", nil, slog.LevelDebug, []slog.Level{slog.LevelInfo}, []slog.Level{slog.LevelWarn}},
+		{"custom levels", []ClientOption{WithLogLevels(slog.LevelDebug, slog.LevelError)}, slog.LevelDebug, []slog.Level{slog.LevelDebug}, []slog.Level{slog.LevelError}},
+		{"successes below the handler level", []ClientOption{WithLogLevels(slog.LevelDebug, slog.LevelError)}, slog.LevelInfo, nil, []slog.Level{slog.LevelError}},
+		{"failures below the handler level", nil, slog.LevelError, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newLoggingServer(t)
+			h := &captureHandler{level: tt.handlerLevel}
+			opts := append([]ClientOption{WithBaseURL(srv.URL), WithLogger(slog.New(h))}, tt.opts...)
+			client := NewClient("test_key", opts...)
+			ctx := context.Background()
+
+			if _, err := client.Orders.Get(ctx, "ord_1"); err != nil {
+				t.Fatal(err)
+			}
+			if got := levels(h.take()); !slices.Equal(got, tt.success) {
+				t.Errorf("success logged at %v, want %v", got, tt.success)
+			}
+			if _, err := client.Orders.Get(ctx, "ord_missing"); err == nil {
+				t.Fatal("Get of a missing order succeeded")
+			}
+			if got := levels(h.take()); !slices.Equal(got, tt.failure) {
+				t.Errorf("failure logged at %v, want %v", got, tt.failure)
+			}
+		})
+	}
+}
+
+func levels(records []slog.Record) []slog.Level {
+	var out []slog.Level
+	for _, r := range records {
+		out = append(out, r.Level)
+	}
+	return out
+}
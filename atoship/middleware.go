@@ -0,0 +1,39 @@
+package atoship
+
+import "net/http"
+
+// RoundTripFunc sends a single HTTP request and returns its response
+type RoundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip implements http.RoundTripper
+func (f RoundTripFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the transport used for every API call. It can inspect
+// or modify the outgoing request, short-circuit it with its own response,
+// or observe the response returned by next.
+//
+// Middleware runs once per attempt, so a retried call passes through it
+// several times.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// WithMiddleware appends middleware to the client's transport chain. The
+// first middleware given is the outermost and sees the request first.
+func WithMiddleware(middleware ...Middleware) ClientOption {
+	return func(c *Client) {
+		c.middleware = append(c.middleware, middleware...)
+	}
+}
+
+// chainTransport wraps base in the given middleware, outermost first
+func chainTransport(base http.RoundTripper, middleware []Middleware) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	next := RoundTripFunc(base.RoundTrip)
+	for i := len(middleware) - 1; i >= 0; i-- {
+		next = middleware[i](next)
+	}
+	return next
+}
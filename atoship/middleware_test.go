@@ -0,0 +1,188 @@
+package atoship
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// tracing returns middleware appending name before and after each call to
+// *calls
+func tracing(name string, calls *[]string) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			*calls = append(*calls, name+" before")
+			resp, err := next(req)
+			*calls = append(*calls, name+" after")
+			return resp, err
+		}
+	}
+}
+
+func okServer(t *testing.T) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"success":true,"data":[]}`)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	srv := okServer(t)
+	var calls []string
+	client := NewClient("test_key",
+		WithBaseURL(srv.URL),
+		WithMiddleware(tracing("first", &calls), tracing("second", &calls)),
+		WithMiddleware(tracing("third", &calls)),
+	)
+	if _, err := client.Carriers.List(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"first before", "second before", "third before", "third after", "second after", "first after"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("calls = %q, want %q", calls, want)
+	}
+}
+
+func TestMiddlewareSeesRetries(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if hits.Add(1) <= 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			fmt.Fprint(w, `{"success":false,"error":{"code":"SERVER_ERROR","message":"try again"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"success":true,"data":{"id":"ord_1"}}`)
+	}))
+	defer srv.Close()
+
+	var statuses []int
+	var keys []string
+	record := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			keys = append(keys, req.Header.Get(headerIdempotencyKey))
+			resp, err := next(req)
+			if err == nil {
+				statuses = append(statuses, resp.StatusCode)
+			}
+			return resp, err
+		}
+	}
+	client := NewClient("test_key",
+		WithBaseURL(srv.URL),
+		WithMiddleware(record),
+		WithRetryPolicy(RetryPolicy{MaxRetries: 3, InitialBackoff: time.Millisecond}),
+	)
+	var meta ResponseMeta
+	if _, err := client.Orders.Create(WithResponseMeta(context.Background(), &meta), &CreateOrderRequest{OrderNumber: "ORD-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []int{503, 503, 200}; !slices.Equal(statuses, want) {
+		t.Errorf("middleware saw statuses %v, want one per attempt %v", statuses, want)
+	}
+	if meta.Attempts != 3 {
+		t.Errorf("Attempts = %d, want 3", meta.Attempts)
+	}
+	// Every attempt is the same call, so carries the same key
+	if keys[0] == "" || keys[1] != keys[0] || keys[2] != keys[0] {
+		t.Errorf("idempotency keys = %q, want one key on every attempt", keys)
+	}
+}
+
+func TestMiddlewareOutsideDebugAndCompression(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Encoding") != "gzip" {
+			t.Errorf("server got Content-Encoding %q, want gzip", r.Header.Get("Content-Encoding"))
+		}
+		if r.Header.Get("X-Middleware") != "outer" {
+			t.Errorf("server got X-Middleware %q, want the header set by the middleware", r.Header.Get("X-Middleware"))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		fmt.Fprint(zw, `{"success":true,"data":{"id":"ord_1","orderNumber":"ORD-1"}}`)
+		zw.Close()
+	}))
+	defer srv.Close()
+
+	var reqBody, respBody []byte
+	var reqEncoding, respEncoding string
+	outer := func(next RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			req.Header.Set("X-Middleware", "outer")
+			reqEncoding = req.Header.Get("Content-Encoding")
+			reqBody, _ = io.ReadAll(req.Body)
+			req.Body = io.NopCloser(bytes.NewReader(reqBody))
+
+			resp, err := next(req)
+			if err != nil {
+				return resp, err
+			}
+			respEncoding = resp.Header.Get("Content-Encoding")
+			respBody, _ = io.ReadAll(resp.Body)
+			resp.Body = io.NopCloser(bytes.NewReader(respBody))
+			return resp, nil
+		}
+	}
+
+	var log logRecorder
+	client := NewClient("test_key",
+		WithBaseURL(srv.URL),
+		WithMiddleware(outer),
+		WithDebugLogger(&log),
+		WithCompression(1),
+	)
+	order, err := client.Orders.Create(context.Background(), &CreateOrderRequest{OrderNumber: "ORD-1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.ID != "ord_1" {
+		t.Errorf("order = %+v", order)
+	}
+
+	// Compression happens inside the middleware, in both directions
+	if reqEncoding != "" || !bytes.Contains(reqBody, []byte(`"orderNumber":"ORD-1"`)) {
+		t.Errorf("middleware saw a %q request body %q, want plain JSON", reqEncoding, reqBody)
+	}
+	if respEncoding != "" || !bytes.Contains(respBody, []byte(`"id":"ord_1"`)) {
+		t.Errorf("middleware saw a %q response body %q, want plain JSON", respEncoding, respBody)
+	}
+
+	// Debug logging happens inside too, so it logs the middleware's header
+	if !strings.Contains(log.String(), "X-Middleware: outer") {
+		t.Errorf("debug log does not show the middleware's header:\n%s", log.String())
+	}
+
+	// A middleware answering by itself bypasses debug logging entirely
+	log.Reset()
+	shortCircuit := func(RoundTripFunc) RoundTripFunc {
+		return func(req *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Type": {"application/json"}},
+				Body:       io.NopCloser(strings.NewReader(`{"success":true,"data":[]}`)),
+				Request:    req,
+			}, nil
+		}
+	}
+	client = NewClient("test_key", WithBaseURL(srv.URL), WithMiddleware(shortCircuit), WithDebugLogger(&log), WithCompression(1))
+	if _, err := client.Carriers.List(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if log.Len() != 0 {
+		t.Errorf("debug log for a short-circuited call:\n%s", log.String())
+	}
+}
@@ -3,6 +3,8 @@ package atoship
 import (
 	"context"
 	"fmt"
+	"net/url"
+	"strconv"
 	"time"
 )
 
@@ -161,20 +163,101 @@ func (s *OrdersService) Update(ctx context.Context, orderID string, req *UpdateO
 	return &order, err
 }
 
+// values encodes the options as URL query parameters
+func (o *ListOrdersOptions) values() url.Values {
+	v := url.Values{}
+	if o.Page > 0 {
+		v.Set("page", strconv.Itoa(o.Page))
+	}
+	if o.Limit > 0 {
+		v.Set("limit", strconv.Itoa(o.Limit))
+	}
+	if o.Status != "" {
+		v.Set("status", o.Status)
+	}
+	if o.Source != "" {
+		v.Set("source", o.Source)
+	}
+	if o.Search != "" {
+		v.Set("search", o.Search)
+	}
+	if o.StartDate != "" {
+		v.Set("startDate", o.StartDate)
+	}
+	if o.EndDate != "" {
+		v.Set("endDate", o.EndDate)
+	}
+	if o.SortBy != "" {
+		v.Set("sortBy", o.SortBy)
+	}
+	if o.SortOrder != "" {
+		v.Set("sortOrder", o.SortOrder)
+	}
+	return v
+}
+
 // List lists orders with optional filters
 func (s *OrdersService) List(ctx context.Context, opts *ListOrdersOptions) (*OrderListResponse, error) {
 	var resp OrderListResponse
 	path := "/api/orders"
-	
+
 	// Add query parameters
 	if opts != nil {
-		// TODO: Build query string from options
+		if q := opts.values().Encode(); q != "" {
+			path += "?" + q
+		}
 	}
-	
+
 	err := s.client.get(ctx, path, &resp)
 	return &resp, err
 }
 
+// ListPager returns a Pager over every order matching opts, starting at
+// opts.Page (or the first page)
+func (s *OrdersService) ListPager(opts *ListOrdersOptions) *Pager[Order] {
+	filter := ListOrdersOptions{}
+	if opts != nil {
+		filter = *opts
+	}
+
+	fetch := func(ctx context.Context, page, limit int) (*Page[Order], error) {
+		pageOpts := filter
+		pageOpts.Page = page
+		pageOpts.Limit = limit
+		resp, err := s.List(ctx, &pageOpts)
+		if err != nil {
+			return nil, err
+		}
+		return &Page[Order]{
+			Items:   resp.Orders,
+			Total:   resp.Total,
+			Page:    resp.Page,
+			Limit:   resp.Limit,
+			HasMore: resp.HasMore,
+		}, nil
+	}
+	return NewPager[Order](fetch, &ListOptions{Page: filter.Page, Limit: filter.Limit})
+}
+
+// ListAll walks every page of orders matching opts, calling fn for each
+// order in turn. Paging starts at opts.Page (or the first page) and stops
+// once the API reports no more results, fn returns an error, or ctx is done.
+func (s *OrdersService) ListAll(ctx context.Context, opts *ListOrdersOptions, fn func(*Order) error) error {
+	pager := s.ListPager(opts)
+	for pager.More() {
+		pg, err := pager.Next(ctx)
+		if err != nil {
+			return err
+		}
+		for i := range pg.Items {
+			if err := fn(&pg.Items[i]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // Delete deletes an order
 func (s *OrdersService) Delete(ctx context.Context, orderID string) error {
 	return s.client.delete(ctx, fmt.Sprintf("/api/orders/%s", orderID))
@@ -203,6 +286,9 @@ func (s *OrdersService) Cancel(ctx context.Context, orderID string, reason strin
 
 // BulkCreate creates multiple orders in batch
 func (s *OrdersService) BulkCreate(ctx context.Context, orders []*CreateOrderRequest) (*BulkCreateResponse, error) {
+	if err := s.client.requireLive("BulkCreate"); err != nil {
+		return nil, err
+	}
 	var resp BulkCreateResponse
 	err := s.client.post(ctx, "/api/orders/batch", map[string]interface{}{
 		"orders": orders,
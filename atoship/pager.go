@@ -0,0 +1,200 @@
+package atoship
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"strconv"
+)
+
+// ErrNoMorePages is returned by Pager.Next once every page has been read
+var ErrNoMorePages = errors.New("atoship: no more pages")
+
+// ListOptions represents paging options for list endpoints
+type ListOptions struct {
+	Page  int `json:"page,omitempty"`
+	Limit int `json:"limit,omitempty"`
+}
+
+// Page represents a single page of results from a list endpoint
+type Page[T any] struct {
+	Items   []T
+	Total   int
+	Page    int
+	Limit   int
+	HasMore bool
+}
+
+// PageFetcher retrieves one page of results. A limit of zero leaves the page
+// size to the API.
+type PageFetcher[T any] func(ctx context.Context, page, limit int) (*Page[T], error)
+
+// pageResult carries the outcome of a prefetched page
+type pageResult[T any] struct {
+	page *Page[T]
+	err  error
+}
+
+// Pager walks a paginated list endpoint one page at a time.
+//
+// A Pager is not safe for concurrent use.
+type Pager[T any] struct {
+	fetch    PageFetcher[T]
+	page     int
+	limit    int
+	done     bool
+	prefetch bool
+	pending  chan pageResult[T]
+}
+
+// NewPager returns a Pager that retrieves pages with fetch, starting at
+// opts.Page (or the first page). It is mostly useful for building fakes of
+// the list endpoints.
+func NewPager[T any](fetch PageFetcher[T], opts *ListOptions) *Pager[T] {
+	p := &Pager[T]{fetch: fetch, page: 1}
+	if opts != nil {
+		if opts.Page > 0 {
+			p.page = opts.Page
+		}
+		p.limit = opts.Limit
+	}
+	return p
+}
+
+// PageSize sets the number of items requested per page. A size of zero
+// leaves the choice to the API.
+func (p *Pager[T]) PageSize(n int) *Pager[T] {
+	p.limit = n
+	return p
+}
+
+// Prefetch enables fetching the following page in the background while the
+// caller processes the current one. The background request runs under the
+// context passed to the Next call that triggered it.
+func (p *Pager[T]) Prefetch(enabled bool) *Pager[T] {
+	p.prefetch = enabled
+	return p
+}
+
+// More reports whether another page may be available
+func (p *Pager[T]) More() bool {
+	return !p.done
+}
+
+// Next retrieves the next page of results. It returns ErrNoMorePages once
+// the final page has been read.
+func (p *Pager[T]) Next(ctx context.Context) (*Page[T], error) {
+	if p.done {
+		return nil, ErrNoMorePages
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var pg *Page[T]
+	var err error
+	if p.pending != nil {
+		select {
+		case res := <-p.pending:
+			p.pending = nil
+			pg, err = res.page, res.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	} else {
+		pg, err = p.fetch(ctx, p.page, p.limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// An empty page guards against a server that keeps reporting more
+	if !pg.HasMore || len(pg.Items) == 0 {
+		p.done = true
+		return pg, nil
+	}
+
+	p.page++
+	if p.prefetch {
+		ch := make(chan pageResult[T], 1)
+		go func(page, limit int) {
+			next, err := p.fetch(ctx, page, limit)
+			ch <- pageResult[T]{page: next, err: err}
+		}(p.page, p.limit)
+		p.pending = ch
+	}
+
+	return pg, nil
+}
+
+// All reads every remaining page and returns the combined items
+func (p *Pager[T]) All(ctx context.Context) ([]T, error) {
+	var items []T
+	for p.More() {
+		pg, err := p.Next(ctx)
+		if err != nil {
+			return items, err
+		}
+		items = append(items, pg.Items...)
+	}
+	return items, nil
+}
+
+// newListPager returns a Pager for an endpoint that responds with a
+// PaginatedResponse. Endpoints that still return a bare JSON array are
+// treated as a single, final page.
+func newListPager[T any](c *Client, path string, opts *ListOptions) *Pager[T] {
+	fetch := func(ctx context.Context, page, limit int) (*Page[T], error) {
+		q := url.Values{}
+		q.Set("page", strconv.Itoa(page))
+		if limit > 0 {
+			q.Set("limit", strconv.Itoa(limit))
+		}
+
+		var raw json.RawMessage
+		if err := c.get(ctx, path+"?"+q.Encode(), &raw); err != nil {
+			return nil, err
+		}
+		pg, err := decodePage[T](raw)
+		if err != nil {
+			return nil, &DecodeError{Body: raw, Err: err}
+		}
+		return pg, nil
+	}
+	return NewPager[T](fetch, opts)
+}
+
+// decodePage decodes a PaginatedResponse (or bare array) into a typed page
+func decodePage[T any](raw json.RawMessage) (*Page[T], error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return &Page[T]{}, nil
+	}
+
+	if raw[0] == '[' {
+		var items []T
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return nil, err
+		}
+		return &Page[T]{Items: items, Total: len(items)}, nil
+	}
+
+	var resp PaginatedResponse
+	if err := json.Unmarshal(raw, &resp); err != nil {
+		return nil, err
+	}
+	pg := &Page[T]{
+		Total:   resp.Total,
+		Page:    resp.Page,
+		Limit:   resp.Limit,
+		HasMore: resp.HasMore,
+	}
+	if len(resp.Items) > 0 {
+		if err := json.Unmarshal(resp.Items, &pg.Items); err != nil {
+			return nil, err
+		}
+	}
+	return pg, nil
+}
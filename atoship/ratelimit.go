@@ -0,0 +1,139 @@
+package atoship
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RateLimitStatus reports the quota most recently advertised by the API
+// through its X-RateLimit-* response headers
+type RateLimitStatus struct {
+	// Limit is the number of requests allowed per window
+	Limit int
+	// Remaining is the number of requests left in the current window
+	Remaining int
+	// Reset is when the current window ends
+	Reset time.Time
+	// UpdatedAt is when the status was last observed; zero if the API has
+	// not sent rate-limit headers yet
+	UpdatedAt time.Time
+}
+
+// WithRateLimit limits the client to requestsPerSecond, allowing bursts of
+// up to burst requests. The limit is shared by every service and goroutine
+// using the client. When the API reports its quota is exhausted, requests
+// are held until the advertised reset time.
+func WithRateLimit(requestsPerSecond float64, burst int) ClientOption {
+	return func(c *Client) {
+		if burst < 1 {
+			burst = 1
+		}
+		c.limiter.rate = requestsPerSecond
+		c.limiter.burst = float64(burst)
+		c.limiter.tokens = float64(burst)
+	}
+}
+
+// RateLimitStatus returns the last quota reported by the API
+func (c *Client) RateLimitStatus() RateLimitStatus {
+	c.limiter.mu.Lock()
+	defer c.limiter.mu.Unlock()
+	return c.limiter.status
+}
+
+// rateLimiter is a token bucket that also tracks server-side quota
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	status RateLimitStatus
+}
+
+// wait blocks until a request may be sent or ctx is done
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	if l.rate <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+
+	now := time.Now()
+	if !l.last.IsZero() {
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+
+	// Reserve a token, possibly going into debt until it refills
+	l.tokens--
+	var delay time.Duration
+	if l.tokens < 0 {
+		delay = time.Duration(-l.tokens / l.rate * float64(time.Second))
+	}
+
+	// Hold off entirely while the server says the quota is spent
+	if l.status.Remaining <= 0 && !l.status.UpdatedAt.IsZero() && l.status.Reset.After(now) {
+		if d := l.status.Reset.Sub(now); d > delay {
+			delay = d
+		}
+	}
+	l.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// observe records the quota advertised in response headers
+func (l *rateLimiter) observe(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.status.Remaining = remaining
+	l.status.UpdatedAt = now
+	if limit, err := strconv.Atoi(header.Get("X-RateLimit-Limit")); err == nil {
+		l.status.Limit = limit
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		l.status.Reset = parseRateLimitReset(reset, now)
+	}
+
+	// Never hold more local tokens than the server is willing to honor
+	if l.rate > 0 && float64(remaining) < l.tokens {
+		l.tokens = float64(remaining)
+	}
+}
+
+// parseRateLimitReset interprets X-RateLimit-Reset, which APIs send either
+// as a Unix timestamp or as seconds until the window resets
+func parseRateLimitReset(value int64, now time.Time) time.Time {
+	if value > 1_000_000_000 {
+		return time.Unix(value, 0)
+	}
+	return now.Add(time.Duration(value) * time.Second)
+}
@@ -0,0 +1,166 @@
+package atoship
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// timeWait returns how long l.wait blocked
+func timeWait(t *testing.T, l *rateLimiter) time.Duration {
+	t.Helper()
+	start := time.Now()
+	if err := l.wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return time.Since(start)
+}
+
+func TestRateLimiterBurstThenRefill(t *testing.T) {
+	l := &rateLimiter{rate: 20, burst: 3, tokens: 3}
+
+	for i := 0; i < 3; i++ {
+		if d := timeWait(t, l); d > 10*time.Millisecond {
+			t.Errorf("request %d of the burst waited %v", i+1, d)
+		}
+	}
+	// The bucket is empty, so the next request waits for one token
+	if d := timeWait(t, l); d < 40*time.Millisecond || d > 150*time.Millisecond {
+		t.Errorf("request after the burst waited %v, want about 50ms", d)
+	}
+
+	// A long pause refills the bucket, but only up to the burst
+	time.Sleep(300 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		if d := timeWait(t, l); d > 10*time.Millisecond {
+			t.Errorf("request %d after refilling waited %v", i+1, d)
+		}
+	}
+	if d := timeWait(t, l); d < 40*time.Millisecond {
+		t.Errorf("request after the refilled burst waited %v, want about 50ms", d)
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	l := &rateLimiter{}
+	l.observe(http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"60"}})
+	if d := timeWait(t, l); d > 10*time.Millisecond {
+		t.Errorf("wait without WithRateLimit took %v", d)
+	}
+}
+
+func TestRateLimiterWaitsForReset(t *testing.T) {
+	l := &rateLimiter{rate: 1000, burst: 10, tokens: 10}
+	l.observe(http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {"1"}})
+	if d := timeWait(t, l); d < 900*time.Millisecond || d > 1500*time.Millisecond {
+		t.Errorf("wait with the quota spent took %v, want about 1s", d)
+	}
+
+	// A reset time in the past no longer holds requests
+	l = &rateLimiter{rate: 1000, burst: 10, tokens: 10}
+	l.observe(http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)}})
+	if d := timeWait(t, l); d > 50*time.Millisecond {
+		t.Errorf("wait after the reset took %v", d)
+	}
+
+	// Remaining quota caps the local tokens without holding requests
+	l = &rateLimiter{rate: 1000, burst: 10, tokens: 10}
+	l.observe(http.Header{"X-Ratelimit-Remaining": {"2"}, "X-Ratelimit-Reset": {"60"}})
+	if l.tokens != 2 {
+		t.Errorf("tokens = %v, want 2", l.tokens)
+	}
+	if d := timeWait(t, l); d > 10*time.Millisecond {
+		t.Errorf("wait with quota remaining took %v", d)
+	}
+}
+
+func TestRateLimiterWaitCancelled(t *testing.T) {
+	l := &rateLimiter{rate: 1, burst: 1, tokens: 1}
+	timeWait(t, l)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := l.wait(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if d := time.Since(start); d > 200*time.Millisecond {
+		t.Errorf("wait returned %v after the context was done", d)
+	}
+
+	// The cancelled request gives its reserved token back
+	l.mu.Lock()
+	tokens := l.tokens
+	l.mu.Unlock()
+	if tokens < -0.1 {
+		t.Errorf("tokens = %v, want the cancelled reservation returned", tokens)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if err := l.wait(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("wait with a cancelled context: err = %v, want context.Canceled", err)
+	}
+}
+
+func TestRateLimitStatus(t *testing.T) {
+	var remaining atomic.Int32
+	remaining.Store(42)
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := remaining.Add(-1)
+		w.Header().Set("X-RateLimit-Limit", "100")
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(int(n)))
+		if n%2 == 0 {
+			w.Header().Set("X-RateLimit-Reset", "30")
+		} else {
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(reset.Unix(), 10))
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/api/orders/ord_missing" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"success":false,"error":{"code":"NOT_FOUND_ERROR","message":"not found"}}`)
+			return
+		}
+		fmt.Fprint(w, `{"success":true,"data":[]}`)
+	}))
+	defer srv.Close()
+
+	client := NewClient("test_key", WithBaseURL(srv.URL))
+	if status := client.RateLimitStatus(); status != (RateLimitStatus{}) {
+		t.Errorf("status before any call = %+v, want zero", status)
+	}
+
+	ctx := context.Background()
+	before := time.Now()
+	if _, err := client.Carriers.List(ctx); err != nil {
+		t.Fatal(err)
+	}
+	status := client.RateLimitStatus()
+	if status.Limit != 100 || status.Remaining != 41 || status.UpdatedAt.Before(before) {
+		t.Errorf("status = %+v", status)
+	}
+	if !status.Reset.Equal(reset) {
+		t.Errorf("Reset = %v, want the Unix timestamp %v", status.Reset, reset)
+	}
+
+	// Error responses carry the headers too, and the last one wins
+	before = time.Now()
+	if _, err := client.Orders.Get(ctx, "ord_missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("err = %v, want ErrNotFound", err)
+	}
+	status = client.RateLimitStatus()
+	if status.Remaining != 40 {
+		t.Errorf("Remaining = %d, want 40", status.Remaining)
+	}
+	if d := status.Reset.Sub(before); d < 30*time.Second || d > 31*time.Second {
+		t.Errorf("Reset is %v away, want the relative 30s", d)
+	}
+}
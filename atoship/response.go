@@ -0,0 +1,67 @@
+package atoship
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// ResponseMeta describes the final response received for an API call
+type ResponseMeta struct {
+	// RequestID identifies the call to atoship support
+	RequestID string
+	// StatusCode is the HTTP status of the final attempt; zero if no
+	// response was received
+	StatusCode int
+	// Header holds the HTTP headers of the final attempt
+	Header http.Header
+	// Timestamp is the server timestamp from the response envelope
+	Timestamp string
+	// Latency is the total time spent on the call, including retries
+	Latency time.Duration
+	// Attempts is the number of times the request was sent
+	Attempts int
+}
+
+// Retries returns the number of retries made after the first attempt
+func (m *ResponseMeta) Retries() int {
+	if m.Attempts <= 1 {
+		return 0
+	}
+	return m.Attempts - 1
+}
+
+type responseMetaContextKey struct{}
+
+// WithResponseMeta returns a copy of ctx that captures response metadata
+// into meta. The metadata is populated when the call returns, whether or
+// not it succeeded.
+//
+//	var meta atoship.ResponseMeta
+//	order, err := client.Orders.Get(atoship.WithResponseMeta(ctx, &meta), id)
+//	log.Printf("request %s took %s", meta.RequestID, meta.Latency)
+func WithResponseMeta(ctx context.Context, meta *ResponseMeta) context.Context {
+	return context.WithValue(ctx, responseMetaContextKey{}, meta)
+}
+
+// responseMetaFrom returns the ResponseMeta registered on ctx, if any
+func responseMetaFrom(ctx context.Context) *ResponseMeta {
+	meta, _ := ctx.Value(responseMetaContextKey{}).(*ResponseMeta)
+	return meta
+}
+
+// observeResponse records the transport-level details of an attempt
+func (m *ResponseMeta) observeResponse(resp *resty.Response) {
+	m.StatusCode = 0
+	m.Header = nil
+	m.RequestID = ""
+	m.Timestamp = ""
+	if resp == nil || resp.RawResponse == nil {
+		return
+	}
+	m.StatusCode = resp.StatusCode()
+	m.Header = resp.Header()
+	m.RequestID = resp.Header().Get("X-Request-Id")
+}
@@ -0,0 +1,166 @@
+package atoship
+
+import (
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RetryPolicy controls how failed requests are retried.
+//
+// Requests are retried on network errors and on 408, 429, 500, 502, 503 and
+// 504 responses. GET, PUT and DELETE requests are always eligible; POST and
+// PATCH requests are retried only when they carry an Idempotency-Key
+// header. Every POST and PATCH made by the services carries one, generated
+// per call or set with WithIdempotencyKey, so calls such as
+// Shipping.PurchaseLabel are retried too, and the API uses the key to
+// avoid buying the same label twice.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt
+	MaxRetries int
+	// InitialBackoff is the wait before the first retry
+	InitialBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after every attempt
+	Multiplier float64
+	// Jitter randomizes each backoff by up to this fraction (0 to 1)
+	Jitter float64
+	// MaxElapsedTime bounds the total time spent on a call, including
+	// waits. Zero means no limit.
+	MaxElapsedTime time.Duration
+	// MaxRetryAfter is the longest Retry-After the client waits for. When
+	// the server asks for a longer wait, the call fails with its error
+	// instead of retrying.
+	MaxRetryAfter time.Duration
+}
+
+// DefaultRetryPolicy returns the policy used by WithRetryCount
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     30 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		MaxElapsedTime: 2 * time.Minute,
+		MaxRetryAfter:  time.Minute,
+	}
+}
+
+// WithRetryPolicy sets the retry policy. Zero backoff, multiplier and
+// MaxRetryAfter values fall back to those of DefaultRetryPolicy.
+func WithRetryPolicy(policy RetryPolicy) ClientOption {
+	return func(c *Client) {
+		def := DefaultRetryPolicy()
+		if policy.InitialBackoff <= 0 {
+			policy.InitialBackoff = def.InitialBackoff
+		}
+		if policy.MaxBackoff <= 0 {
+			policy.MaxBackoff = def.MaxBackoff
+		}
+		if policy.MaxRetryAfter <= 0 {
+			policy.MaxRetryAfter = def.MaxRetryAfter
+		}
+		if policy.Multiplier < 1 {
+			policy.Multiplier = def.Multiplier
+		}
+		if policy.Jitter < 0 {
+			policy.Jitter = 0
+		} else if policy.Jitter > 1 {
+			policy.Jitter = 1
+		}
+		c.retryPolicy = policy
+	}
+}
+
+// retryableMethod reports whether a request may be replayed safely
+func retryableMethod(method string, header http.Header) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return header.Get(headerIdempotencyKey) != ""
+}
+
+// retryableStatus reports whether a response status is worth retrying
+func retryableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// backoff returns the wait before the given retry (1-based)
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	d := float64(p.InitialBackoff) * math.Pow(p.Multiplier, float64(retry-1))
+	if limit := float64(p.MaxBackoff); d > limit {
+		d = limit
+	}
+	if p.Jitter > 0 {
+		d *= 1 - p.Jitter + 2*p.Jitter*rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// retryDelay decides whether the failed attempt should be retried and, if
+// so, how long to wait first. A Retry-After header from the server takes
+// precedence over the computed backoff, unless it exceeds MaxRetryAfter,
+// which ends the retries.
+func (p RetryPolicy) retryDelay(attempt int, started time.Time, method string, header http.Header, resp *resty.Response, transportErr bool) (time.Duration, bool) {
+	if attempt > p.MaxRetries {
+		return 0, false
+	}
+	if !retryableMethod(method, header) {
+		return 0, false
+	}
+	if !transportErr && (resp == nil || !retryableStatus(resp.StatusCode())) {
+		return 0, false
+	}
+
+	wait := p.backoff(attempt)
+	if resp != nil {
+		if after, ok := parseRetryAfter(resp.Header().Get("Retry-After"), time.Now()); ok {
+			if p.MaxRetryAfter > 0 && after > p.MaxRetryAfter {
+				return 0, false
+			}
+			wait = after
+		}
+	}
+
+	if p.MaxElapsedTime > 0 && time.Since(started)+wait > p.MaxElapsedTime {
+		return 0, false
+	}
+	return wait, true
+}
+
+// parseRetryAfter parses a Retry-After header given in seconds or as an
+// HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		if d := at.Sub(now); d > 0 {
+			return d, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
@@ -0,0 +1,147 @@
+package atoship
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-resty/resty/v2"
+)
+
+func response(status int, header http.Header) *resty.Response {
+	if header == nil {
+		header = http.Header{}
+	}
+	return &resty.Response{RawResponse: &http.Response{StatusCode: status, Header: header}}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"empty", "", 0, false},
+		{"seconds", "7", 7 * time.Second, true},
+		{"zero seconds", "0", 0, true},
+		{"negative seconds", "-3", 0, false},
+		{"http date", now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second, true},
+		{"http date in the past", now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"garbage", "soon", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := parseRetryAfter(tt.value, now)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	policy := RetryPolicy{
+		MaxRetries:     3,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+	keyed := http.Header{headerIdempotencyKey: {"key"}}
+
+	tests := []struct {
+		name      string
+		policy    RetryPolicy
+		attempt   int
+		started   time.Time
+		method    string
+		header    http.Header
+		resp      *resty.Response
+		transport bool
+		wait      time.Duration
+		retry     bool
+	}{
+		{name: "5xx backs off", attempt: 1, method: "GET", resp: response(503, nil), wait: 100 * time.Millisecond, retry: true},
+		{name: "backoff grows", attempt: 3, method: "GET", resp: response(502, nil), wait: 400 * time.Millisecond, retry: true},
+		{name: "backoff is capped", policy: RetryPolicy{MaxRetries: 10, InitialBackoff: time.Second, MaxBackoff: 3 * time.Second, Multiplier: 2}, attempt: 5, method: "GET", resp: response(500, nil), wait: 3 * time.Second, retry: true},
+		{name: "network error", attempt: 1, method: "GET", transport: true, wait: 100 * time.Millisecond, retry: true},
+		{name: "429 honors Retry-After seconds", attempt: 1, method: "GET", resp: response(429, http.Header{"Retry-After": {"2"}}), wait: 2 * time.Second, retry: true},
+		{name: "400 is not retried", attempt: 1, method: "GET", resp: response(400, nil)},
+		{name: "404 is not retried", attempt: 1, method: "GET", resp: response(404, nil)},
+		{name: "422 is not retried", attempt: 1, method: "PUT", resp: response(422, nil)},
+		{name: "retries exhausted", attempt: 4, method: "GET", resp: response(503, nil)},
+		{name: "POST without key", attempt: 1, method: "POST", resp: response(503, nil)},
+		{name: "POST with key", attempt: 1, method: "POST", header: keyed, resp: response(503, nil), wait: 100 * time.Millisecond, retry: true},
+		{name: "MaxElapsedTime cuts off", policy: RetryPolicy{MaxRetries: 3, InitialBackoff: time.Second, MaxBackoff: time.Second, Multiplier: 1, MaxElapsedTime: 5 * time.Second}, attempt: 1, started: time.Now().Add(-4500 * time.Millisecond), method: "GET", resp: response(503, nil)},
+		{name: "MaxElapsedTime not reached", policy: RetryPolicy{MaxRetries: 3, InitialBackoff: time.Second, MaxBackoff: time.Second, Multiplier: 1, MaxElapsedTime: 5 * time.Second}, attempt: 1, started: time.Now().Add(-time.Second), method: "GET", resp: response(503, nil), wait: time.Second, retry: true},
+		{name: "Retry-After past MaxElapsedTime", policy: RetryPolicy{MaxRetries: 3, InitialBackoff: time.Second, MaxBackoff: time.Second, Multiplier: 1, MaxElapsedTime: 5 * time.Second}, attempt: 1, method: "GET", resp: response(429, http.Header{"Retry-After": {"60"}})},
+		{name: "Retry-After up to MaxRetryAfter", policy: RetryPolicy{MaxRetries: 3, InitialBackoff: time.Second, MaxBackoff: time.Second, Multiplier: 1, MaxRetryAfter: time.Minute}, attempt: 1, method: "GET", resp: response(429, http.Header{"Retry-After": {"60"}}), wait: time.Minute, retry: true},
+		{name: "Retry-After over MaxRetryAfter", policy: RetryPolicy{MaxRetries: 3, InitialBackoff: time.Second, MaxBackoff: time.Second, Multiplier: 1, MaxRetryAfter: time.Minute}, attempt: 1, method: "GET", resp: response(429, http.Header{"Retry-After": {"86400"}})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := tt.policy
+			if p.MaxRetries == 0 {
+				p = policy
+			}
+			started := tt.started
+			if started.IsZero() {
+				started = time.Now()
+			}
+			header := tt.header
+			if header == nil {
+				header = http.Header{}
+			}
+			wait, retry := p.retryDelay(tt.attempt, started, tt.method, header, tt.resp, tt.transport)
+			if wait != tt.wait || retry != tt.retry {
+				t.Errorf("retryDelay = %v, %v; want %v, %v", wait, retry, tt.wait, tt.retry)
+			}
+		})
+	}
+}
+
+func TestRetryDelayHTTPDate(t *testing.T) {
+	at := time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat)
+	wait, retry := DefaultRetryPolicy().retryDelay(1, time.Now(), "GET", http.Header{}, response(503, http.Header{"Retry-After": {at}}), false)
+	if !retry || wait < 28*time.Second || wait > 30*time.Second {
+		t.Errorf("retryDelay = %v, %v; want about 30s, true", wait, retry)
+	}
+}
+
+func TestRetryAfterOverLimitFailsPromptly(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Retry-After", "86400")
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"success":false,"error":{"code":"RATE_LIMIT_ERROR","message":"daily quota exceeded"}}`)
+	}))
+	defer srv.Close()
+
+	// No MaxElapsedTime, so only MaxRetryAfter stops a day-long wait
+	client := NewClient("test_key", WithBaseURL(srv.URL), WithRetryPolicy(RetryPolicy{MaxRetries: 3}))
+	if client.retryPolicy.MaxRetryAfter != DefaultRetryPolicy().MaxRetryAfter {
+		t.Errorf("MaxRetryAfter = %v, want the default", client.retryPolicy.MaxRetryAfter)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err := client.Carriers.List(ctx)
+	if !errors.Is(err, ErrRateLimited) {
+		t.Errorf("err = %v, want ErrRateLimited", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("call took %v, want it to fail without waiting", d)
+	}
+	if n := hits.Load(); n != 1 {
+		t.Errorf("server saw %d attempts, want 1", n)
+	}
+}
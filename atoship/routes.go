@@ -0,0 +1,95 @@
+package atoship
+
+import "strings"
+
+// route describes one API endpoint
+type route struct {
+	method    string
+	template  string
+	operation string
+	segments  []string
+}
+
+// routes lists the endpoints called by the services. Literal segments are
+// listed before parameters so that, for example, /api/orders/batch is not
+// taken for an order ID.
+var routes = newRoutes([][3]string{
+	{"GET", "/api/orders", "orders.list"},
+	{"POST", "/api/orders", "orders.create"},
+	{"POST", "/api/orders/batch", "orders.bulk_create"},
+	{"GET", "/api/orders/{orderId}", "orders.get"},
+	{"PUT", "/api/orders/{orderId}", "orders.update"},
+	{"DELETE", "/api/orders/{orderId}", "orders.delete"},
+	{"POST", "/api/orders/{orderId}/ship", "orders.ship"},
+	{"POST", "/api/orders/{orderId}/cancel", "orders.cancel"},
+
+	{"GET", "/api/addresses", "addresses.list"},
+	{"POST", "/api/addresses", "addresses.create"},
+	{"POST", "/api/addresses/validate", "addresses.validate"},
+	{"GET", "/api/addresses/{addressId}", "addresses.get"},
+	{"PUT", "/api/addresses/{addressId}", "addresses.update"},
+	{"DELETE", "/api/addresses/{addressId}", "addresses.delete"},
+	{"GET", "/api/address-search", "addresses.search"},
+
+	{"GET", "/api/carriers", "carriers.list"},
+	{"POST", "/api/carriers/smart-rates", "shipping.get_rates"},
+	{"POST", "/api/labels/purchase-v2", "shipping.purchase_label"},
+	{"GET", "/api/labels/{labelId}", "shipping.get_label"},
+	{"POST", "/api/labels/{labelId}/cancel", "shipping.cancel_label"},
+
+	{"POST", "/api/tracking/batch", "tracking.batch_track"},
+	{"GET", "/api/tracking/{trackingNumber}", "tracking.track"},
+
+	{"GET", "/api/profile", "users.get_profile"},
+	{"PUT", "/api/profile", "users.update_profile"},
+
+	{"GET", "/api/admin/stats", "admin.get_stats"},
+
+	{"GET", "/api/admin/webhooks", "webhooks.list"},
+	{"POST", "/api/admin/webhooks", "webhooks.create"},
+	{"GET", "/api/admin/webhooks/{webhookId}", "webhooks.get"},
+	{"PATCH", "/api/admin/webhooks/{webhookId}", "webhooks.update"},
+	{"DELETE", "/api/admin/webhooks/{webhookId}", "webhooks.delete"},
+	{"POST", "/api/admin/webhooks/{webhookId}/rotate-secret", "webhooks.rotate_secret"},
+	{"POST", "/api/admin/webhooks/{webhookId}/test", "webhooks.send_test"},
+	{"GET", "/api/admin/webhooks/{webhookId}/deliveries", "webhooks.list_deliveries"},
+	{"POST", "/api/admin/webhooks/{webhookId}/deliveries/{deliveryId}/retry", "webhooks.retry_delivery"},
+})
+
+func newRoutes(table [][3]string) []route {
+	out := make([]route, len(table))
+	for i, r := range table {
+		out[i] = route{method: r[0], template: r[1], operation: r[2], segments: strings.Split(r[1], "/")}
+	}
+	return out
+}
+
+// matchRoute returns the path template, such as /api/orders/{orderId}, and
+// operation name, such as orders.get, of a request. Paths outside the table
+// are returned without their query string and with an empty operation.
+func matchRoute(method, path string) (template, operation string) {
+	path, _, _ = strings.Cut(path, "?")
+	segments := strings.Split(path, "/")
+	for _, r := range routes {
+		if r.method == method && matchSegments(r.segments, segments) {
+			return r.template, r.operation
+		}
+	}
+	return path, ""
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) != len(segments) {
+		return false
+	}
+	for i, p := range pattern {
+		if strings.HasPrefix(p, "{") {
+			if segments[i] == "" {
+				return false
+			}
+		} else if p != segments[i] {
+			return false
+		}
+	}
+	return true
+}
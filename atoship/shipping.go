@@ -90,6 +90,9 @@ func (s *ShippingService) GetRates(ctx context.Context, req *RateRequest) ([]Shi
 
 // PurchaseLabel purchases a shipping label using V2 API with routing engine
 func (s *ShippingService) PurchaseLabel(ctx context.Context, req *PurchaseLabelRequest) (*ShippingLabel, error) {
+	if err := s.client.requireLive("PurchaseLabel"); err != nil {
+		return nil, err
+	}
 	var label ShippingLabel
 	err := s.client.post(ctx, "/api/labels/purchase-v2", req, &label)
 	return &label, err
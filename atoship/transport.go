@@ -0,0 +1,36 @@
+package atoship
+
+import "net/http"
+
+// WithHTTPClient sends requests through a copy of hc, keeping its
+// transport, cookie jar, redirect policy and Timeout. hc itself is never
+// modified. WithTimeout replaces the Timeout, and a nil Transport gets the
+// default transport described at WithTransport.
+func WithHTTPClient(hc *http.Client) ClientOption {
+	return func(c *Client) {
+		c.baseClient = hc
+	}
+}
+
+// WithTransport sends requests through transport, for example an
+// *http.Transport with a corporate proxy, custom root CAs, client
+// certificates for mutual TLS or tuned connection pooling. It takes
+// precedence over the Transport of a client given to WithHTTPClient.
+// Middleware, including debug logging, wraps transport, which may be
+// shared with other clients.
+//
+// Without WithTransport, the SDK uses resty's default transport, which
+// reads the proxy from HTTP_PROXY, HTTPS_PROXY and NO_PROXY, attempts
+// HTTP/2, and pools connections with:
+//
+//	dial timeout and TCP keep-alive    30s
+//	TLS handshake timeout              10s
+//	MaxIdleConns                       100
+//	MaxIdleConnsPerHost                GOMAXPROCS + 1
+//	IdleConnTimeout                    90s
+//	ExpectContinueTimeout              1s
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
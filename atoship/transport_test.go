@@ -0,0 +1,38 @@
+package atoship
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestHTTPClientTimeout(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []ClientOption
+		want time.Duration
+	}{
+		{"default", nil, DefaultTimeout},
+		{"WithTimeout", []ClientOption{WithTimeout(5 * time.Second)}, 5 * time.Second},
+		{"WithHTTPClient keeps its timeout", []ClientOption{WithHTTPClient(&http.Client{Timeout: 7 * time.Second})}, 7 * time.Second},
+		{"WithHTTPClient without a timeout", []ClientOption{WithHTTPClient(&http.Client{})}, 0},
+		{"WithTimeout overrides WithHTTPClient", []ClientOption{WithHTTPClient(&http.Client{Timeout: 7 * time.Second}), WithTimeout(2 * time.Second)}, 2 * time.Second},
+		{"WithTimeout before WithHTTPClient", []ClientOption{WithTimeout(2 * time.Second), WithHTTPClient(&http.Client{Timeout: 7 * time.Second})}, 2 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewClient("test_key", tt.opts...)
+			if got := client.httpClient.GetClient().Timeout; got != tt.want {
+				t.Errorf("Timeout = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithHTTPClientDoesNotModifyClient(t *testing.T) {
+	hc := &http.Client{Timeout: 7 * time.Second}
+	NewClient("test_key", WithHTTPClient(hc), WithTimeout(time.Second), WithTransport(http.DefaultTransport))
+	if hc.Timeout != 7*time.Second || hc.Transport != nil {
+		t.Errorf("client given to WithHTTPClient was modified: %+v", hc)
+	}
+}
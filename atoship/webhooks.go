@@ -3,6 +3,7 @@ package atoship
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // WebhooksService handles webhook-related operations
@@ -19,13 +20,64 @@ type Webhook struct {
 	Secret  string   `json:"secret,omitempty"`
 }
 
-// CreateWebhookRequest represents a request to create a webhook
+// Webhook event types
+const (
+	EventOrderCreated      = "order.created"
+	EventOrderUpdated      = "order.updated"
+	EventOrderShipped      = "order.shipped"
+	EventOrderCancelled    = "order.cancelled"
+	EventLabelPurchased    = "label.purchased"
+	EventLabelCancelled    = "label.cancelled"
+	EventTrackingUpdated   = "tracking.updated"
+	EventTrackingDelivered = "tracking.delivered"
+	EventTrackingException = "tracking.exception"
+)
+
+// WebhookEventTypes returns every webhook event type
+func WebhookEventTypes() []string {
+	return []string{
+		EventOrderCreated,
+		EventOrderUpdated,
+		EventOrderShipped,
+		EventOrderCancelled,
+		EventLabelPurchased,
+		EventLabelCancelled,
+		EventTrackingUpdated,
+		EventTrackingDelivered,
+		EventTrackingException,
+	}
+}
+
+// CreateWebhookRequest represents a request to create a webhook. Events
+// lists the event types to deliver, such as EventOrderCreated.
 type CreateWebhookRequest struct {
 	URL    string   `json:"url"`
 	Events []string `json:"events"`
 	Active bool     `json:"active,omitempty"`
 }
 
+// UpdateWebhookRequest represents a request to update a webhook. Empty
+// fields are left unchanged.
+type UpdateWebhookRequest struct {
+	URL    string   `json:"url,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+// WebhookDelivery represents an attempt to deliver an event to a webhook
+type WebhookDelivery struct {
+	ID           string    `json:"id"`
+	WebhookID    string    `json:"webhookId"`
+	EventID      string    `json:"eventId"`
+	EventType    string    `json:"eventType"`
+	Attempt      int       `json:"attempt"`
+	Success      bool      `json:"success"`
+	StatusCode   int       `json:"statusCode,omitempty"`
+	ResponseBody string    `json:"responseBody,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	DurationMs   int64     `json:"durationMs,omitempty"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
 // Create creates a new webhook
 func (s *WebhooksService) Create(ctx context.Context, req *CreateWebhookRequest) (*Webhook, error) {
 	var webhook Webhook
@@ -33,14 +85,78 @@ func (s *WebhooksService) Create(ctx context.Context, req *CreateWebhookRequest)
 	return &webhook, err
 }
 
-// List lists all webhooks
+// List lists all webhooks, following pagination until every page is read
 func (s *WebhooksService) List(ctx context.Context) ([]Webhook, error) {
-	var webhooks []Webhook
-	err := s.client.get(ctx, "/api/admin/webhooks", &webhooks)
-	return webhooks, err
+	return s.ListPager(nil).All(ctx)
+}
+
+// ListPager returns a Pager over the configured webhooks
+func (s *WebhooksService) ListPager(opts *ListOptions) *Pager[Webhook] {
+	return newListPager[Webhook](s.client, "/api/admin/webhooks", opts)
 }
 
 // Delete deletes a webhook
 func (s *WebhooksService) Delete(ctx context.Context, webhookID string) error {
 	return s.client.delete(ctx, fmt.Sprintf("/api/admin/webhooks/%s", webhookID))
-}
\ No newline at end of file
+}
+
+// Get retrieves a webhook by ID
+func (s *WebhooksService) Get(ctx context.Context, webhookID string) (*Webhook, error) {
+	var webhook Webhook
+	err := s.client.get(ctx, fmt.Sprintf("/api/admin/webhooks/%s", webhookID), &webhook)
+	return &webhook, err
+}
+
+// Update changes a webhook's URL or subscribed events
+func (s *WebhooksService) Update(ctx context.Context, webhookID string, req *UpdateWebhookRequest) (*Webhook, error) {
+	var webhook Webhook
+	err := s.client.patch(ctx, fmt.Sprintf("/api/admin/webhooks/%s", webhookID), req, &webhook)
+	return &webhook, err
+}
+
+// SetActive enables or disables delivery to a webhook
+func (s *WebhooksService) SetActive(ctx context.Context, webhookID string, active bool) (*Webhook, error) {
+	req := map[string]bool{
+		"active": active,
+	}
+	var webhook Webhook
+	err := s.client.patch(ctx, fmt.Sprintf("/api/admin/webhooks/%s", webhookID), req, &webhook)
+	return &webhook, err
+}
+
+// RotateSecret replaces a webhook's signing secret and returns the new one
+func (s *WebhooksService) RotateSecret(ctx context.Context, webhookID string) (string, error) {
+	var webhook Webhook
+	err := s.client.post(ctx, fmt.Sprintf("/api/admin/webhooks/%s/rotate-secret", webhookID), nil, &webhook)
+	return webhook.Secret, err
+}
+
+// SendTest sends a sample event of the given type to a webhook and returns
+// the resulting delivery
+func (s *WebhooksService) SendTest(ctx context.Context, webhookID string, eventType string) (*WebhookDelivery, error) {
+	req := map[string]string{
+		"eventType": eventType,
+	}
+	var delivery WebhookDelivery
+	err := s.client.post(ctx, fmt.Sprintf("/api/admin/webhooks/%s/test", webhookID), req, &delivery)
+	return &delivery, err
+}
+
+// ListDeliveries lists a webhook's delivery attempts, most recent first,
+// following pagination until every page is read
+func (s *WebhooksService) ListDeliveries(ctx context.Context, webhookID string) ([]WebhookDelivery, error) {
+	return s.ListDeliveriesPager(webhookID, nil).All(ctx)
+}
+
+// ListDeliveriesPager returns a Pager over a webhook's delivery attempts,
+// most recent first
+func (s *WebhooksService) ListDeliveriesPager(webhookID string, opts *ListOptions) *Pager[WebhookDelivery] {
+	return newListPager[WebhookDelivery](s.client, fmt.Sprintf("/api/admin/webhooks/%s/deliveries", webhookID), opts)
+}
+
+// RetryDelivery redelivers the event of an earlier delivery attempt
+func (s *WebhooksService) RetryDelivery(ctx context.Context, webhookID, deliveryID string) (*WebhookDelivery, error) {
+	var delivery WebhookDelivery
+	err := s.client.post(ctx, fmt.Sprintf("/api/admin/webhooks/%s/deliveries/%s/retry", webhookID, deliveryID), nil, &delivery)
+	return &delivery, err
+}
@@ -0,0 +1,326 @@
+package webhooks
+
+import (
+	"bufio"
+	"container/list"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRetention is how long event IDs are remembered by default. atoship
+// stops retrying a delivery well within this window.
+const DefaultRetention = 72 * time.Hour
+
+// DefaultMemoryCapacity is the number of event IDs a MemoryStore keeps by
+// default
+const DefaultMemoryCapacity = 100000
+
+// DedupeStore records the IDs of events that have been processed. Handler
+// claims each event ID before calling user code and releases it again if
+// the callback fails, so that a redelivery is processed.
+type DedupeStore interface {
+	// Claim marks eventID as processed and reports whether this call
+	// claimed it; false means the event was already claimed within the
+	// retention window
+	Claim(ctx context.Context, eventID string) (bool, error)
+	// Release forgets eventID so a later delivery can claim it again
+	Release(ctx context.Context, eventID string) error
+}
+
+// WithDedupe drops deliveries whose event ID has already been claimed in
+// store, acknowledging them without calling the HandlerFunc. Events
+// without an ID are always processed.
+func WithDedupe(store DedupeStore) Option {
+	return func(h *Handler) {
+		h.dedupe = store
+	}
+}
+
+// MemoryStore is an in-memory DedupeStore that remembers up to a fixed
+// number of event IDs, evicting the least recently claimed first. It is
+// safe for concurrent use but does not survive a restart; use FileStore or
+// a shared database when that matters.
+//
+// Once full, a MemoryStore evicts the oldest ID even if its retention has
+// not run out, so a redelivery of that event is processed again. Size the
+// capacity above the number of events expected within the retention
+// window, and watch Evictions to tell when it is too small.
+type MemoryStore struct {
+	capacity  int
+	retention time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	order     *list.List
+	entries   map[string]*list.Element
+	evictions int64
+}
+
+// memoryEntry is an event ID held by a MemoryStore
+type memoryEntry struct {
+	id      string
+	expires time.Time
+}
+
+// NewMemoryStore returns a MemoryStore holding up to capacity event IDs for
+// retention each. Non-positive values use DefaultMemoryCapacity and
+// DefaultRetention.
+func NewMemoryStore(capacity int, retention time.Duration) *MemoryStore {
+	if capacity <= 0 {
+		capacity = DefaultMemoryCapacity
+	}
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	return &MemoryStore{
+		capacity:  capacity,
+		retention: retention,
+		now:       time.Now,
+		order:     list.New(),
+		entries:   make(map[string]*list.Element),
+	}
+}
+
+// Claim implements DedupeStore
+func (m *MemoryStore) Claim(ctx context.Context, eventID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if el, ok := m.entries[eventID]; ok {
+		if now.Before(el.Value.(*memoryEntry).expires) {
+			return false, nil
+		}
+		m.remove(el)
+	}
+
+	m.entries[eventID] = m.order.PushFront(&memoryEntry{id: eventID, expires: now.Add(m.retention)})
+	for m.order.Len() > m.capacity {
+		oldest := m.order.Back()
+		if now.Before(oldest.Value.(*memoryEntry).expires) {
+			m.evictions++
+		}
+		m.remove(oldest)
+	}
+	return true, nil
+}
+
+// Release implements DedupeStore
+func (m *MemoryStore) Release(ctx context.Context, eventID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if el, ok := m.entries[eventID]; ok {
+		m.remove(el)
+	}
+	return nil
+}
+
+// Len returns the number of event IDs held, including expired ones not yet
+// evicted
+func (m *MemoryStore) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.order.Len()
+}
+
+// Evictions returns the number of event IDs dropped to make room before
+// their retention ran out. A growing count means duplicates of those events
+// are no longer detected and the capacity should be raised.
+func (m *MemoryStore) Evictions() int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.evictions
+}
+
+func (m *MemoryStore) remove(el *list.Element) {
+	m.order.Remove(el)
+	delete(m.entries, el.Value.(*memoryEntry).id)
+}
+
+// FileStore is a DedupeStore that persists event IDs to an append-only
+// file, so duplicates are still detected after a restart. The file is
+// compacted when it is opened and whenever most of its records are stale.
+// A FileStore must only be used by one process at a time.
+type FileStore struct {
+	path      string
+	retention time.Duration
+	now       func() time.Time
+
+	mu        sync.Mutex
+	file      *os.File
+	entries   map[string]time.Time
+	records   int
+	compacted time.Time
+}
+
+// OpenFileStore opens or creates a FileStore at path that remembers event
+// IDs for retention. A non-positive retention uses DefaultRetention.
+func OpenFileStore(path string, retention time.Duration) (*FileStore, error) {
+	if retention <= 0 {
+		retention = DefaultRetention
+	}
+	s := &FileStore{
+		path:      path,
+		retention: retention,
+		now:       time.Now,
+		entries:   make(map[string]time.Time),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	if err := s.compact(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Claim implements DedupeStore
+func (s *FileStore) Claim(ctx context.Context, eventID string) (bool, error) {
+	if strings.ContainsAny(eventID, " \n") {
+		return false, fmt.Errorf("webhooks: invalid event ID %q", eventID)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if expires, ok := s.entries[eventID]; ok && now.Before(expires) {
+		return false, nil
+	}
+	expires := now.Add(s.retention)
+	if err := s.append(eventID, expires); err != nil {
+		return false, err
+	}
+	s.entries[eventID] = expires
+	return true, nil
+}
+
+// Release implements DedupeStore
+func (s *FileStore) Release(ctx context.Context, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.entries[eventID]; !ok {
+		return nil
+	}
+	if err := s.append(eventID, time.Time{}); err != nil {
+		return err
+	}
+	delete(s.entries, eventID)
+	return nil
+}
+
+// Close closes the underlying file
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// load reads the records in the file. Each line is "<expiry> <event ID>",
+// where the expiry is in Unix seconds and zero marks a release; later
+// records replace earlier ones.
+func (s *FileStore) load() error {
+	f, err := os.Open(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	now := s.now()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		ts, id, ok := strings.Cut(scanner.Text(), " ")
+		if !ok {
+			continue
+		}
+		secs, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil {
+			continue
+		}
+		if expires := time.Unix(secs, 0); secs > 0 && now.Before(expires) {
+			s.entries[id] = expires
+		} else {
+			delete(s.entries, id)
+		}
+	}
+	return scanner.Err()
+}
+
+// compact rewrites the file with only the live entries and reopens it for
+// appending. The caller must hold s.mu or have exclusive access.
+func (s *FileStore) compact() error {
+	now := s.now()
+	tmp := s.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for id, expires := range s.entries {
+		if !now.Before(expires) {
+			delete(s.entries, id)
+			continue
+		}
+		fmt.Fprintf(w, "%d %s\n", expires.Unix(), id)
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+
+	if s.file != nil {
+		s.file.Close()
+	}
+	s.file, err = os.OpenFile(s.path, os.O_APPEND|os.O_WRONLY, 0o600)
+	s.records = len(s.entries)
+	s.compacted = now
+	return err
+}
+
+// append writes a record and syncs it to disk, compacting the file first
+// when most of its records are stale or entries may have expired since the
+// last compaction. The caller must hold s.mu.
+func (s *FileStore) append(eventID string, expires time.Time) error {
+	if s.file == nil {
+		return os.ErrClosed
+	}
+	if s.records > 1024 && (s.records > 2*len(s.entries) || s.now().Sub(s.compacted) > s.retention) {
+		if err := s.compact(); err != nil {
+			return err
+		}
+	}
+
+	var secs int64
+	if !expires.IsZero() {
+		secs = expires.Unix()
+	}
+	if _, err := fmt.Fprintf(s.file, "%d %s\n", secs, eventID); err != nil {
+		return err
+	}
+	s.records++
+	return s.file.Sync()
+}
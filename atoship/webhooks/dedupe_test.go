@@ -0,0 +1,172 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// clock is a manually advanced time source for the stores
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func claim(t *testing.T, store DedupeStore, id string, want bool) {
+	t.Helper()
+	got, err := store.Claim(context.Background(), id)
+	if err != nil {
+		t.Fatalf("Claim(%q): %v", id, err)
+	}
+	if got != want {
+		t.Errorf("Claim(%q) = %v, want %v", id, got, want)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	c := &clock{t: testNow}
+	store := NewMemoryStore(10, time.Hour)
+	store.now = c.now
+
+	claim(t, store, "evt_1", true)
+	claim(t, store, "evt_1", false)
+	claim(t, store, "evt_2", true)
+
+	if err := store.Release(context.Background(), "evt_1"); err != nil {
+		t.Fatal(err)
+	}
+	claim(t, store, "evt_1", true)
+
+	c.advance(time.Hour)
+	claim(t, store, "evt_2", true)
+	if store.Len() != 2 {
+		t.Errorf("Len = %d, want 2", store.Len())
+	}
+}
+
+func TestMemoryStoreEviction(t *testing.T) {
+	c := &clock{t: testNow}
+	store := NewMemoryStore(2, time.Hour)
+	store.now = c.now
+
+	claim(t, store, "evt_1", true)
+	claim(t, store, "evt_2", true)
+	claim(t, store, "evt_3", true)
+	if store.Len() != 2 {
+		t.Errorf("Len = %d, want 2", store.Len())
+	}
+	if store.Evictions() != 1 {
+		t.Errorf("Evictions = %d, want 1", store.Evictions())
+	}
+	// evt_1 was evicted early, so its duplicate is no longer detected
+	claim(t, store, "evt_1", true)
+	claim(t, store, "evt_3", false)
+
+	// Dropping expired IDs is not counted
+	c.advance(time.Hour)
+	claim(t, store, "evt_4", true)
+	claim(t, store, "evt_5", true)
+	if store.Evictions() != 2 {
+		t.Errorf("Evictions = %d, want 2", store.Evictions())
+	}
+}
+
+// openFileStore opens a FileStore at path that uses now from the start, so
+// loading and compaction see the same time as later claims
+func openFileStore(t *testing.T, path string, now func() time.Time) *FileStore {
+	t.Helper()
+	s := &FileStore{path: path, retention: time.Hour, now: now, entries: make(map[string]time.Time)}
+	if err := s.load(); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.compact(); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestFileStoreReopen(t *testing.T) {
+	c := &clock{t: testNow}
+	path := filepath.Join(t.TempDir(), "events")
+
+	store := openFileStore(t, path, c.now)
+	claim(t, store, "evt_claimed", true)
+	claim(t, store, "evt_claimed", false)
+	claim(t, store, "evt_released", true)
+	if err := store.Release(context.Background(), "evt_released"); err != nil {
+		t.Fatal(err)
+	}
+	c.advance(30 * time.Minute)
+	claim(t, store, "evt_late", true)
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	store = openFileStore(t, path, c.now)
+	claim(t, store, "evt_claimed", false)
+	claim(t, store, "evt_late", false)
+	claim(t, store, "evt_released", true)
+	store.Close()
+
+	// evt_claimed has expired; evt_late and the second claim of
+	// evt_released have not
+	c.advance(45 * time.Minute)
+	store = openFileStore(t, path, c.now)
+	defer store.Close()
+	claim(t, store, "evt_claimed", true)
+	claim(t, store, "evt_late", false)
+	claim(t, store, "evt_released", false)
+}
+
+func TestFileStoreErrors(t *testing.T) {
+	store, err := OpenFileStore(filepath.Join(t.TempDir(), "events"), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Claim(context.Background(), "evt 1"); err == nil {
+		t.Error("Claim with a space in the ID succeeded")
+	}
+	store.Close()
+	if _, err := store.Claim(context.Background(), "evt_1"); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Claim after Close: err = %v, want os.ErrClosed", err)
+	}
+}
+
+func TestHandlerDedupe(t *testing.T) {
+	const body = `{"id":"evt_1","type":"order.created"}`
+	signature := Sign([]byte(body), testSecret, testNow)
+
+	calls := 0
+	fail := true
+	h := newTestHandler(func(context.Context, *Event) error {
+		calls++
+		if fail {
+			return errors.New("try again")
+		}
+		return nil
+	}, WithDedupe(NewMemoryStore(0, 0)))
+
+	tests := []struct {
+		name  string
+		fail  bool
+		want  int
+		calls int
+	}{
+		{"failed delivery", true, http.StatusInternalServerError, 1},
+		{"redelivery after failure", false, http.StatusNoContent, 2},
+		{"duplicate", false, http.StatusNoContent, 2},
+	}
+	for _, tt := range tests {
+		fail = tt.fail
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, delivery(body, signature))
+		if rec.Code != tt.want || calls != tt.calls {
+			t.Errorf("%s: status = %d, calls = %d; want %d, %d", tt.name, rec.Code, calls, tt.want, tt.calls)
+		}
+	}
+}
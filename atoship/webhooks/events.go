@@ -0,0 +1,231 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+// Errors returned by Mux for deliveries that can never succeed. Handler
+// responds to them with a 400 status so they are not retried.
+var (
+	ErrUnknownEventType = errors.New("webhooks: unknown event type")
+	ErrMalformedEvent   = errors.New("webhooks: malformed event data")
+)
+
+// OrderCreatedEvent is delivered for atoship.EventOrderCreated
+type OrderCreatedEvent struct {
+	Event *Event `json:"-"`
+	atoship.Order
+}
+
+// OrderUpdatedEvent is delivered for atoship.EventOrderUpdated
+type OrderUpdatedEvent struct {
+	Event *Event `json:"-"`
+	atoship.Order
+}
+
+// OrderShippedEvent is delivered for atoship.EventOrderShipped
+type OrderShippedEvent struct {
+	Event *Event `json:"-"`
+	atoship.Order
+}
+
+// OrderCancelledEvent is delivered for atoship.EventOrderCancelled
+type OrderCancelledEvent struct {
+	Event *Event `json:"-"`
+	atoship.Order
+}
+
+// LabelPurchasedEvent is delivered for atoship.EventLabelPurchased
+type LabelPurchasedEvent struct {
+	Event *Event `json:"-"`
+	atoship.ShippingLabel
+}
+
+// LabelCancelledEvent is delivered for atoship.EventLabelCancelled
+type LabelCancelledEvent struct {
+	Event *Event `json:"-"`
+	atoship.ShippingLabel
+}
+
+// TrackingUpdatedEvent is delivered for atoship.EventTrackingUpdated
+type TrackingUpdatedEvent struct {
+	Event *Event `json:"-"`
+	atoship.TrackingInfo
+}
+
+// TrackingDeliveredEvent is delivered for atoship.EventTrackingDelivered
+type TrackingDeliveredEvent struct {
+	Event *Event `json:"-"`
+	atoship.TrackingInfo
+}
+
+// TrackingExceptionEvent is delivered for atoship.EventTrackingException
+type TrackingExceptionEvent struct {
+	Event *Event `json:"-"`
+	atoship.TrackingInfo
+}
+
+// Mux dispatches events to handlers registered by event type. Its
+// HandleEvent method is a HandlerFunc:
+//
+//	mux := webhooks.NewMux()
+//	mux.OnTrackingUpdated(func(ctx context.Context, e *webhooks.TrackingUpdatedEvent) error {
+//		return notifyCustomer(ctx, e.TrackingNumber, e.Status)
+//	})
+//	http.Handle("/webhooks/atoship", webhooks.NewHandler(secret, mux.HandleEvent))
+//
+// Events without a registered handler are acknowledged and ignored. A
+// strict Mux instead fails events whose type is neither registered nor one
+// of atoship.WebhookEventTypes with ErrUnknownEventType.
+type Mux struct {
+	// Strict rejects event types atoship does not define and no handler
+	// is registered for
+	Strict bool
+
+	handlers map[string]HandlerFunc
+}
+
+// NewMux returns an empty Mux
+func NewMux() *Mux {
+	return &Mux{handlers: make(map[string]HandlerFunc)}
+}
+
+// Handle registers fn for events of the given type, replacing any earlier
+// registration
+func (m *Mux) Handle(eventType string, fn HandlerFunc) {
+	m.handlers[eventType] = fn
+}
+
+// HandleEvent dispatches event to the handler registered for its type
+func (m *Mux) HandleEvent(ctx context.Context, event *Event) error {
+	fn, ok := m.handlers[event.Type]
+	if !ok {
+		if m.Strict && !knownEventType(event.Type) {
+			return fmt.Errorf("%w: %q", ErrUnknownEventType, event.Type)
+		}
+		return nil
+	}
+	return fn(ctx, event)
+}
+
+// knownEventType reports whether atoship defines the event type
+func knownEventType(eventType string) bool {
+	for _, t := range atoship.WebhookEventTypes() {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeData unmarshals the event payload into v
+func decodeData(event *Event, v any) error {
+	if err := json.Unmarshal(event.Data, v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrMalformedEvent, event.Type, err)
+	}
+	return nil
+}
+
+// OnOrderCreated registers fn for order.created events
+func (m *Mux) OnOrderCreated(fn func(context.Context, *OrderCreatedEvent) error) {
+	m.Handle(atoship.EventOrderCreated, func(ctx context.Context, e *Event) error {
+		ev := &OrderCreatedEvent{Event: e}
+		if err := decodeData(e, &ev.Order); err != nil {
+			return err
+		}
+		return fn(ctx, ev)
+	})
+}
+
+// OnOrderUpdated registers fn for order.updated events
+func (m *Mux) OnOrderUpdated(fn func(context.Context, *OrderUpdatedEvent) error) {
+	m.Handle(atoship.EventOrderUpdated, func(ctx context.Context, e *Event) error {
+		ev := &OrderUpdatedEvent{Event: e}
+		if err := decodeData(e, &ev.Order); err != nil {
+			return err
+		}
+		return fn(ctx, ev)
+	})
+}
+
+// OnOrderShipped registers fn for order.shipped events
+func (m *Mux) OnOrderShipped(fn func(context.Context, *OrderShippedEvent) error) {
+	m.Handle(atoship.EventOrderShipped, func(ctx context.Context, e *Event) error {
+		ev := &OrderShippedEvent{Event: e}
+		if err := decodeData(e, &ev.Order); err != nil {
+			return err
+		}
+		return fn(ctx, ev)
+	})
+}
+
+// OnOrderCancelled registers fn for order.cancelled events
+func (m *Mux) OnOrderCancelled(fn func(context.Context, *OrderCancelledEvent) error) {
+	m.Handle(atoship.EventOrderCancelled, func(ctx context.Context, e *Event) error {
+		ev := &OrderCancelledEvent{Event: e}
+		if err := decodeData(e, &ev.Order); err != nil {
+			return err
+		}
+		return fn(ctx, ev)
+	})
+}
+
+// OnLabelPurchased registers fn for label.purchased events
+func (m *Mux) OnLabelPurchased(fn func(context.Context, *LabelPurchasedEvent) error) {
+	m.Handle(atoship.EventLabelPurchased, func(ctx context.Context, e *Event) error {
+		ev := &LabelPurchasedEvent{Event: e}
+		if err := decodeData(e, &ev.ShippingLabel); err != nil {
+			return err
+		}
+		return fn(ctx, ev)
+	})
+}
+
+// OnLabelCancelled registers fn for label.cancelled events
+func (m *Mux) OnLabelCancelled(fn func(context.Context, *LabelCancelledEvent) error) {
+	m.Handle(atoship.EventLabelCancelled, func(ctx context.Context, e *Event) error {
+		ev := &LabelCancelledEvent{Event: e}
+		if err := decodeData(e, &ev.ShippingLabel); err != nil {
+			return err
+		}
+		return fn(ctx, ev)
+	})
+}
+
+// OnTrackingUpdated registers fn for tracking.updated events
+func (m *Mux) OnTrackingUpdated(fn func(context.Context, *TrackingUpdatedEvent) error) {
+	m.Handle(atoship.EventTrackingUpdated, func(ctx context.Context, e *Event) error {
+		ev := &TrackingUpdatedEvent{Event: e}
+		if err := decodeData(e, &ev.TrackingInfo); err != nil {
+			return err
+		}
+		return fn(ctx, ev)
+	})
+}
+
+// OnTrackingDelivered registers fn for tracking.delivered events
+func (m *Mux) OnTrackingDelivered(fn func(context.Context, *TrackingDeliveredEvent) error) {
+	m.Handle(atoship.EventTrackingDelivered, func(ctx context.Context, e *Event) error {
+		ev := &TrackingDeliveredEvent{Event: e}
+		if err := decodeData(e, &ev.TrackingInfo); err != nil {
+			return err
+		}
+		return fn(ctx, ev)
+	})
+}
+
+// OnTrackingException registers fn for tracking.exception events
+func (m *Mux) OnTrackingException(fn func(context.Context, *TrackingExceptionEvent) error) {
+	m.Handle(atoship.EventTrackingException, func(ctx context.Context, e *Event) error {
+		ev := &TrackingExceptionEvent{Event: e}
+		if err := decodeData(e, &ev.TrackingInfo); err != nil {
+			return err
+		}
+		return fn(ctx, ev)
+	})
+}
@@ -0,0 +1,113 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+func event(eventType, data string) *Event {
+	return &Event{ID: "evt_1", Type: eventType, Data: json.RawMessage(data)}
+}
+
+func TestMuxDispatch(t *testing.T) {
+	ctx := context.Background()
+	mux := NewMux()
+
+	var order *OrderCreatedEvent
+	mux.OnOrderCreated(func(_ context.Context, e *OrderCreatedEvent) error {
+		order = e
+		return nil
+	})
+	var label *LabelPurchasedEvent
+	mux.OnLabelPurchased(func(_ context.Context, e *LabelPurchasedEvent) error {
+		label = e
+		return nil
+	})
+	var tracking *TrackingDeliveredEvent
+	mux.OnTrackingDelivered(func(_ context.Context, e *TrackingDeliveredEvent) error {
+		tracking = e
+		return nil
+	})
+
+	if err := mux.HandleEvent(ctx, event(atoship.EventOrderCreated, `{"id":"ord_1","orderNumber":"ORD-1","recipientName":"Jane Doe"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if order == nil || order.ID != "ord_1" || order.OrderNumber != "ORD-1" || order.RecipientName != "Jane Doe" || order.Event.ID != "evt_1" {
+		t.Errorf("order event = %+v", order)
+	}
+
+	if err := mux.HandleEvent(ctx, event(atoship.EventLabelPurchased, `{"id":"lbl_1","trackingNumber":"1Z999"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if label == nil || label.ID != "lbl_1" || label.TrackingNumber != "1Z999" || label.Event.Type != atoship.EventLabelPurchased {
+		t.Errorf("label event = %+v", label)
+	}
+
+	if err := mux.HandleEvent(ctx, event(atoship.EventTrackingDelivered, `{"trackingNumber":"1Z999","status":"DELIVERED"}`)); err != nil {
+		t.Fatal(err)
+	}
+	if tracking == nil || tracking.TrackingNumber != "1Z999" || tracking.Status != "DELIVERED" {
+		t.Errorf("tracking event = %+v", tracking)
+	}
+}
+
+func TestMuxHandlerError(t *testing.T) {
+	mux := NewMux()
+	failure := errors.New("database unavailable")
+	mux.Handle("custom.event", func(context.Context, *Event) error { return failure })
+
+	if err := mux.HandleEvent(context.Background(), event("custom.event", `{}`)); !errors.Is(err, failure) {
+		t.Errorf("err = %v, want the handler's error", err)
+	}
+}
+
+func TestMuxMalformedEvent(t *testing.T) {
+	mux := NewMux()
+	called := false
+	mux.OnTrackingUpdated(func(context.Context, *TrackingUpdatedEvent) error {
+		called = true
+		return nil
+	})
+
+	err := mux.HandleEvent(context.Background(), event(atoship.EventTrackingUpdated, `{"trackingNumber":42}`))
+	if !errors.Is(err, ErrMalformedEvent) {
+		t.Errorf("err = %v, want ErrMalformedEvent", err)
+	}
+	if called {
+		t.Error("handler was called with malformed data")
+	}
+}
+
+func TestMuxStrict(t *testing.T) {
+	tests := []struct {
+		name      string
+		strict    bool
+		eventType string
+		want      error
+	}{
+		{"handled type", false, atoship.EventOrderCreated, nil},
+		{"known type without handler", false, atoship.EventOrderCancelled, nil},
+		{"unknown type", false, "order.teleported", nil},
+		{"strict handled type", true, atoship.EventOrderCreated, nil},
+		{"strict known type without handler", true, atoship.EventOrderCancelled, nil},
+		{"strict unknown type", true, "order.teleported", ErrUnknownEventType},
+		{"strict custom registered type", true, "custom.event", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mux := NewMux()
+			mux.Strict = tt.strict
+			mux.OnOrderCreated(func(context.Context, *OrderCreatedEvent) error { return nil })
+			mux.Handle("custom.event", func(context.Context, *Event) error { return nil })
+
+			err := mux.HandleEvent(context.Background(), event(tt.eventType, `{}`))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
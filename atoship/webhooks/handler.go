@@ -0,0 +1,205 @@
+// Package webhooks receives atoship webhook deliveries.
+//
+// Handler is an http.Handler that verifies each delivery's signature over
+// the raw request body, rejects stale or replayed deliveries, and passes
+// the decoded Event to a callback:
+//
+//	h := webhooks.NewHandler(os.Getenv("ATOSHIP_WEBHOOK_SECRET"),
+//		func(ctx context.Context, event *webhooks.Event) error {
+//			log.Printf("received %s (%s)", event.Type, event.ID)
+//			return nil
+//		})
+//	http.Handle("/webhooks/atoship", h)
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBodyBytes is the largest delivery accepted by default
+const DefaultMaxBodyBytes = 1 << 20
+
+// Event is a webhook delivery
+type Event struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// HandlerFunc processes a verified event. Returning an error responds with
+// a 500 status so the delivery is retried, except for ErrUnknownEventType
+// and ErrMalformedEvent, which respond with a 400.
+type HandlerFunc func(ctx context.Context, event *Event) error
+
+// Handler is an http.Handler that verifies and dispatches webhook
+// deliveries
+type Handler struct {
+	handle       HandlerFunc
+	tolerance    time.Duration
+	maxBodyBytes int64
+	now          func() time.Time
+	errorLog     func(err error)
+	dedupe       DedupeStore
+
+	mu      sync.RWMutex
+	secrets []string
+}
+
+// Option configures a Handler
+type Option func(*Handler)
+
+// WithSecrets accepts deliveries signed with any of the given secrets in
+// addition to the primary one, for use while a secret is being rotated
+func WithSecrets(secrets ...string) Option {
+	return func(h *Handler) {
+		h.secrets = append(h.secrets, secrets...)
+	}
+}
+
+// WithTolerance sets the maximum age of an accepted delivery. A tolerance
+// of zero disables the check, which leaves the handler open to replays.
+func WithTolerance(tolerance time.Duration) Option {
+	return func(h *Handler) {
+		h.tolerance = tolerance
+	}
+}
+
+// WithMaxBodyBytes sets the largest delivery accepted
+func WithMaxBodyBytes(n int64) Option {
+	return func(h *Handler) {
+		h.maxBodyBytes = n
+	}
+}
+
+// WithErrorLog reports deliveries that were rejected or failed processing
+func WithErrorLog(fn func(err error)) Option {
+	return func(h *Handler) {
+		h.errorLog = fn
+	}
+}
+
+// NewHandler returns a Handler that verifies deliveries signed with secret
+// and passes them to fn
+func NewHandler(secret string, fn HandlerFunc, opts ...Option) *Handler {
+	h := &Handler{
+		handle:       fn,
+		tolerance:    DefaultTolerance,
+		maxBodyBytes: DefaultMaxBodyBytes,
+		now:          time.Now,
+	}
+	if secret != "" {
+		h.secrets = []string{secret}
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+// SetSecrets replaces the accepted signing secrets, allowing a secret to
+// be rotated without restarting the server
+func (h *Handler) SetSecrets(secrets ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.secrets = append([]string(nil), secrets...)
+}
+
+// ServeHTTP implements http.Handler
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	event, status, err := h.parse(r)
+	if err != nil {
+		h.logError(err)
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+
+	if h.dedupe != nil && event.ID != "" {
+		claimed, err := h.dedupe.Claim(r.Context(), event.ID)
+		if err != nil {
+			h.logError(err)
+			http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			return
+		}
+		if !claimed {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+	}
+
+	if err := h.handle(r.Context(), event); err != nil {
+		h.logError(err)
+		if h.dedupe != nil && event.ID != "" {
+			if err := h.dedupe.Release(context.Background(), event.ID); err != nil {
+				h.logError(err)
+			}
+		}
+		status := http.StatusInternalServerError
+		if errors.Is(err, ErrUnknownEventType) || errors.Is(err, ErrMalformedEvent) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Parse verifies a delivery and decodes its event without dispatching it
+func (h *Handler) Parse(r *http.Request) (*Event, error) {
+	event, _, err := h.parse(r)
+	return event, err
+}
+
+// parse verifies and decodes a delivery, returning the HTTP status to use
+// when it is rejected
+func (h *Handler) parse(r *http.Request) (*Event, int, error) {
+	body, err := io.ReadAll(io.LimitReader(r.Body, h.maxBodyBytes+1))
+	if err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if int64(len(body)) > h.maxBodyBytes {
+		return nil, http.StatusRequestEntityTooLarge, errors.New("webhooks: payload too large")
+	}
+
+	h.mu.RLock()
+	secrets := h.secrets
+	h.mu.RUnlock()
+
+	if err := Verify(body, r.Header.Get(SignatureHeader), secrets, h.tolerance, h.now()); err != nil {
+		status := http.StatusUnauthorized
+		switch {
+		case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrMalformedHeader):
+			status = http.StatusBadRequest
+		case errors.Is(err, ErrNoSecrets):
+			status = http.StatusInternalServerError
+		}
+		return nil, status, err
+	}
+
+	var event Event
+	if err := json.Unmarshal(body, &event); err != nil {
+		return nil, http.StatusBadRequest, err
+	}
+	if event.Type == "" {
+		return nil, http.StatusBadRequest, errors.New("webhooks: event has no type")
+	}
+	return &event, http.StatusOK, nil
+}
+
+func (h *Handler) logError(err error) {
+	if h.errorLog != nil {
+		h.errorLog(err)
+	}
+}
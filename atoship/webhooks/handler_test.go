@@ -0,0 +1,139 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "whsec_test"
+
+var testNow = time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestHandler(fn HandlerFunc, opts ...Option) *Handler {
+	h := NewHandler(testSecret, fn, opts...)
+	h.now = func() time.Time { return testNow }
+	return h
+}
+
+func delivery(body, signature string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/webhooks", strings.NewReader(body))
+	if signature != "" {
+		req.Header.Set(SignatureHeader, signature)
+	}
+	return req
+}
+
+func TestHandlerServeHTTP(t *testing.T) {
+	const body = `{"id":"evt_1","type":"order.created","data":{"orderId":"ord_1"}}`
+	signed := Sign([]byte(body), testSecret, testNow)
+	ok := func(context.Context, *Event) error { return nil }
+
+	tests := []struct {
+		name      string
+		method    string
+		body      string
+		signature string
+		noSecrets bool
+		fn        HandlerFunc
+		opts      []Option
+		want      int
+	}{
+		{name: "valid delivery", body: body, signature: signed, fn: ok, want: http.StatusNoContent},
+		{name: "wrong method", method: http.MethodGet, body: body, signature: signed, fn: ok, want: http.StatusMethodNotAllowed},
+		{name: "missing signature", body: body, fn: ok, want: http.StatusBadRequest},
+		{name: "malformed signature", body: body, signature: "v1=abc", fn: ok, want: http.StatusBadRequest},
+		{name: "tampered body", body: strings.Replace(body, "ord_1", "ord_2", 1), signature: signed, fn: ok, want: http.StatusUnauthorized},
+		{name: "wrong secret", body: body, signature: Sign([]byte(body), "whsec_other", testNow), fn: ok, want: http.StatusUnauthorized},
+		{name: "rotated secret", body: body, signature: Sign([]byte(body), "whsec_next", testNow), fn: ok, opts: []Option{WithSecrets("whsec_next")}, want: http.StatusNoContent},
+		{name: "expired timestamp", body: body, signature: Sign([]byte(body), testSecret, testNow.Add(-DefaultTolerance-time.Second)), fn: ok, want: http.StatusUnauthorized},
+		{name: "future timestamp", body: body, signature: Sign([]byte(body), testSecret, testNow.Add(DefaultTolerance+time.Second)), fn: ok, want: http.StatusUnauthorized},
+		{name: "no secrets configured", body: body, signature: signed, noSecrets: true, fn: ok, want: http.StatusInternalServerError},
+		{name: "oversized body", body: body, signature: signed, fn: ok, opts: []Option{WithMaxBodyBytes(int64(len(body) - 1))}, want: http.StatusRequestEntityTooLarge},
+		{name: "body at the limit", body: body, signature: signed, fn: ok, opts: []Option{WithMaxBodyBytes(int64(len(body)))}, want: http.StatusNoContent},
+		{name: "invalid JSON", body: "{", signature: Sign([]byte("{"), testSecret, testNow), fn: ok, want: http.StatusBadRequest},
+		{name: "event without type", body: `{"id":"evt_1"}`, signature: Sign([]byte(`{"id":"evt_1"}`), testSecret, testNow), fn: ok, want: http.StatusBadRequest},
+		{name: "unknown event type", body: body, signature: signed, fn: func(context.Context, *Event) error {
+			return fmt.Errorf("%w: %q", ErrUnknownEventType, "order.created")
+		}, want: http.StatusBadRequest},
+		{name: "malformed event data", body: body, signature: signed, fn: func(context.Context, *Event) error {
+			return fmt.Errorf("%w: order.created: bad data", ErrMalformedEvent)
+		}, want: http.StatusBadRequest},
+		{name: "handler error", body: body, signature: signed, fn: func(context.Context, *Event) error {
+			return errors.New("database unavailable")
+		}, want: http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(tt.fn, tt.opts...)
+			if tt.noSecrets {
+				h.SetSecrets()
+			}
+			req := delivery(tt.body, tt.signature)
+			if tt.method != "" {
+				req.Method = tt.method
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestHandlerPassesEvent(t *testing.T) {
+	const body = `{"id":"evt_1","type":"tracking.updated","createdAt":"2024-03-01T11:59:00Z","data":{"status":"IN_TRANSIT"}}`
+	var got *Event
+	h := newTestHandler(func(_ context.Context, event *Event) error {
+		got = event
+		return nil
+	})
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, delivery(body, Sign([]byte(body), testSecret, testNow)))
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("status = %d, want 204", rec.Code)
+	}
+	if got == nil || got.ID != "evt_1" || got.Type != "tracking.updated" || string(got.Data) != `{"status":"IN_TRANSIT"}` {
+		t.Errorf("event = %+v", got)
+	}
+}
+
+func TestHandlerErrorLog(t *testing.T) {
+	var logged []error
+	h := newTestHandler(func(context.Context, *Event) error { return nil }, WithErrorLog(func(err error) {
+		logged = append(logged, err)
+	}))
+
+	h.ServeHTTP(httptest.NewRecorder(), delivery(`{"type":"order.created"}`, ""))
+	if len(logged) != 1 || !errors.Is(logged[0], ErrMissingSignature) {
+		t.Errorf("logged = %v, want [ErrMissingSignature]", logged)
+	}
+}
+
+func TestHandlerSetSecrets(t *testing.T) {
+	const body = `{"id":"evt_1","type":"order.created"}`
+	h := newTestHandler(func(context.Context, *Event) error { return nil })
+	h.SetSecrets("whsec_rotated")
+
+	tests := []struct {
+		secret string
+		want   int
+	}{
+		{testSecret, http.StatusUnauthorized},
+		{"whsec_rotated", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, delivery(body, Sign([]byte(body), tt.secret, testNow)))
+		if rec.Code != tt.want {
+			t.Errorf("signed with %s: status = %d, want %d", tt.secret, rec.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SignatureHeader is the HTTP header carrying the webhook signature. Its
+// value has the form "t=<unix seconds>,v1=<hex HMAC-SHA256>", where the
+// HMAC is computed over "<t>.<raw body>". Several v1 entries may be present
+// while a secret is being rotated.
+const SignatureHeader = "Atoship-Signature"
+
+// DefaultTolerance is the maximum age of a signed delivery accepted by
+// default
+const DefaultTolerance = 5 * time.Minute
+
+// Signature verification errors
+var (
+	ErrMissingSignature = errors.New("webhooks: missing signature header")
+	ErrMalformedHeader  = errors.New("webhooks: malformed signature header")
+	ErrInvalidSignature = errors.New("webhooks: signature does not match payload")
+	ErrTimestampExpired = errors.New("webhooks: timestamp outside tolerance window")
+	ErrNoSecrets        = errors.New("webhooks: no signing secrets configured")
+)
+
+// Sign returns the SignatureHeader value for payload signed with secret at
+// time t
+func Sign(payload []byte, secret string, t time.Time) string {
+	ts := strconv.FormatInt(t.Unix(), 10)
+	return "t=" + ts + ",v1=" + computeSignature(payload, secret, ts)
+}
+
+// Verify checks a SignatureHeader value against payload. The signature must
+// match one of secrets and its timestamp must be within tolerance of now; a
+// tolerance of zero disables the timestamp check.
+func Verify(payload []byte, header string, secrets []string, tolerance time.Duration, now time.Time) error {
+	if len(secrets) == 0 {
+		return ErrNoSecrets
+	}
+	if header == "" {
+		return ErrMissingSignature
+	}
+
+	ts, sigs, err := parseHeader(header)
+	if err != nil {
+		return err
+	}
+
+	if tolerance > 0 {
+		secs, _ := strconv.ParseInt(ts, 10, 64)
+		age := now.Sub(time.Unix(secs, 0))
+		if age > tolerance || age < -tolerance {
+			return ErrTimestampExpired
+		}
+	}
+
+	for _, secret := range secrets {
+		expected := computeSignature(payload, secret, ts)
+		for _, sig := range sigs {
+			if hmac.Equal([]byte(expected), []byte(sig)) {
+				return nil
+			}
+		}
+	}
+	return ErrInvalidSignature
+}
+
+// parseHeader splits a signature header into its timestamp and v1
+// signatures
+func parseHeader(header string) (ts string, sigs []string, err error) {
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			return "", nil, ErrMalformedHeader
+		}
+		switch key {
+		case "t":
+			if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+				return "", nil, ErrMalformedHeader
+			}
+			ts = value
+		case "v1":
+			sigs = append(sigs, value)
+		}
+	}
+	if ts == "" || len(sigs) == 0 {
+		return "", nil, ErrMalformedHeader
+	}
+	return ts, sigs, nil
+}
+
+// computeSignature returns the hex HMAC-SHA256 of "<ts>.<payload>"
+func computeSignature(payload []byte, secret, ts string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ts))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,61 @@
+package webhooks
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	payload := []byte(`{"id":"evt_1","type":"order.created"}`)
+	ts := strconv.FormatInt(now.Unix(), 10)
+
+	tests := []struct {
+		name    string
+		payload []byte
+		header  string
+		secrets []string
+		want    error
+	}{
+		{name: "valid signature", header: Sign(payload, "whsec_a", now), secrets: []string{"whsec_a"}},
+		{name: "tampered body", payload: []byte(`{"id":"evt_1","type":"order.cancelled"}`), header: Sign(payload, "whsec_a", now), secrets: []string{"whsec_a"}, want: ErrInvalidSignature},
+		{name: "wrong secret", header: Sign(payload, "whsec_b", now), secrets: []string{"whsec_a"}, want: ErrInvalidSignature},
+		{name: "second secret during rotation", header: Sign(payload, "whsec_new", now), secrets: []string{"whsec_old", "whsec_new"}},
+		{name: "several v1 entries", header: "t=" + ts + ",v1=" + computeSignature(payload, "whsec_old", ts) + ",v1=" + computeSignature(payload, "whsec_a", ts), secrets: []string{"whsec_a"}},
+		{name: "none of several v1 entries match", header: "t=" + ts + ",v1=deadbeef,v1=" + computeSignature(payload, "whsec_b", ts), secrets: []string{"whsec_a"}, want: ErrInvalidSignature},
+		{name: "unknown scheme ignored", header: Sign(payload, "whsec_a", now) + ",v0=legacy", secrets: []string{"whsec_a"}},
+		{name: "spaces after commas", header: "t=" + ts + ", v1=" + computeSignature(payload, "whsec_a", ts), secrets: []string{"whsec_a"}},
+		{name: "missing header", header: "", secrets: []string{"whsec_a"}, want: ErrMissingSignature},
+		{name: "no secrets", header: Sign(payload, "whsec_a", now), want: ErrNoSecrets},
+		{name: "no timestamp", header: "v1=" + computeSignature(payload, "whsec_a", ts), secrets: []string{"whsec_a"}, want: ErrMalformedHeader},
+		{name: "no signature", header: "t=" + ts, secrets: []string{"whsec_a"}, want: ErrMalformedHeader},
+		{name: "non-numeric timestamp", header: "t=yesterday,v1=" + computeSignature(payload, "whsec_a", ts), secrets: []string{"whsec_a"}, want: ErrMalformedHeader},
+		{name: "entry without equals", header: "t=" + ts + ",v1", secrets: []string{"whsec_a"}, want: ErrMalformedHeader},
+		{name: "just inside tolerance", header: Sign(payload, "whsec_a", now.Add(-DefaultTolerance)), secrets: []string{"whsec_a"}},
+		{name: "just outside tolerance", header: Sign(payload, "whsec_a", now.Add(-DefaultTolerance-time.Second)), secrets: []string{"whsec_a"}, want: ErrTimestampExpired},
+		{name: "future timestamp inside tolerance", header: Sign(payload, "whsec_a", now.Add(DefaultTolerance)), secrets: []string{"whsec_a"}},
+		{name: "future timestamp outside tolerance", header: Sign(payload, "whsec_a", now.Add(DefaultTolerance+time.Second)), secrets: []string{"whsec_a"}, want: ErrTimestampExpired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := tt.payload
+			if body == nil {
+				body = payload
+			}
+			err := Verify(body, tt.header, tt.secrets, DefaultTolerance, now)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyZeroToleranceSkipsTimestampCheck(t *testing.T) {
+	payload := []byte(`{}`)
+	header := Sign(payload, "whsec_a", time.Unix(0, 0))
+	if err := Verify(payload, header, []string{"whsec_a"}, 0, time.Now()); err != nil {
+		t.Errorf("Verify = %v, want nil", err)
+	}
+}
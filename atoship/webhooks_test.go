@@ -0,0 +1,290 @@
+package atoship_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+	"github.com/atoship-LLC/atoship-go/atoship/atoshiptest"
+	"github.com/atoship-LLC/atoship-go/atoship/webhooks"
+)
+
+// receiver is a webhook endpoint that verifies deliveries against the
+// current secret and answers with a configurable status
+type receiver struct {
+	*httptest.Server
+	status atomic.Int32
+
+	mu     sync.Mutex
+	secret string
+	events []webhooks.Event
+	errs   []error
+}
+
+func newReceiver(t *testing.T) *receiver {
+	rc := &receiver{}
+	rc.status.Store(http.StatusNoContent)
+	rc.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		payload, _ := io.ReadAll(r.Body)
+		rc.mu.Lock()
+		err := webhooks.Verify(payload, r.Header.Get(webhooks.SignatureHeader), []string{rc.secret}, webhooks.DefaultTolerance, time.Now())
+		var event webhooks.Event
+		json.Unmarshal(payload, &event)
+		rc.events = append(rc.events, event)
+		rc.errs = append(rc.errs, err)
+		rc.mu.Unlock()
+		w.WriteHeader(int(rc.status.Load()))
+	}))
+	t.Cleanup(rc.Close)
+	return rc
+}
+
+func (rc *receiver) setSecret(secret string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.secret = secret
+}
+
+// last returns the most recent delivery and whether its signature verified
+func (rc *receiver) last(t *testing.T) (webhooks.Event, error) {
+	t.Helper()
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if len(rc.events) == 0 {
+		t.Fatal("receiver got no deliveries")
+	}
+	return rc.events[len(rc.events)-1], rc.errs[len(rc.errs)-1]
+}
+
+// createWebhook creates a webhook pointing at rc
+func createWebhook(t *testing.T, client *atoship.Client, rc *receiver) *atoship.Webhook {
+	t.Helper()
+	wh, err := client.Webhooks.Create(context.Background(), &atoship.CreateWebhookRequest{
+		URL:    rc.URL,
+		Events: []string{atoship.EventOrderCreated},
+		Active: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rc.setSecret(wh.Secret)
+	return wh
+}
+
+func TestWebhooksGetUpdateSetActive(t *testing.T) {
+	ctx := context.Background()
+	client, srv := atoshiptest.NewClient()
+	defer srv.Close()
+	wh := createWebhook(t, client, newReceiver(t))
+
+	got, err := client.Webhooks.Get(ctx, wh.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != wh.ID || got.URL != wh.URL || !got.Active || !slices.Equal(got.Events, wh.Events) {
+		t.Errorf("Get = %+v, want %+v", got, wh)
+	}
+	if _, err := client.Webhooks.Get(ctx, "wh_missing"); !errors.Is(err, atoship.ErrNotFound) {
+		t.Errorf("Get of a missing webhook: err = %v, want ErrNotFound", err)
+	}
+
+	events := []string{atoship.EventOrderCreated, atoship.EventLabelPurchased}
+	updated, err := client.Webhooks.Update(ctx, wh.ID, &atoship.UpdateWebhookRequest{Events: events})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.URL != wh.URL || !slices.Equal(updated.Events, events) || !updated.Active {
+		t.Errorf("Update of the events = %+v", updated)
+	}
+	updated, err = client.Webhooks.Update(ctx, wh.ID, &atoship.UpdateWebhookRequest{URL: "https://example.com/hooks"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.URL != "https://example.com/hooks" || !slices.Equal(updated.Events, events) {
+		t.Errorf("Update of the URL = %+v", updated)
+	}
+	if _, err := client.Webhooks.Update(ctx, "wh_missing", &atoship.UpdateWebhookRequest{URL: "https://example.com"}); !errors.Is(err, atoship.ErrNotFound) {
+		t.Errorf("Update of a missing webhook: err = %v, want ErrNotFound", err)
+	}
+
+	for _, active := range []bool{false, true} {
+		got, err := client.Webhooks.SetActive(ctx, wh.ID, active)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Active != active || got.URL != "https://example.com/hooks" {
+			t.Errorf("SetActive(%v) = %+v", active, got)
+		}
+		if stored := srv.Webhooks()[0]; stored.Active != active {
+			t.Errorf("after SetActive(%v), server has Active = %v", active, stored.Active)
+		}
+	}
+}
+
+func TestWebhooksRotateSecretAndSendTest(t *testing.T) {
+	ctx := context.Background()
+	client, srv := atoshiptest.NewClient()
+	defer srv.Close()
+	rc := newReceiver(t)
+	wh := createWebhook(t, client, rc)
+
+	delivery, err := client.Webhooks.SendTest(ctx, wh.ID, atoship.EventLabelPurchased)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !delivery.Success || delivery.StatusCode != http.StatusNoContent || delivery.WebhookID != wh.ID || delivery.EventType != atoship.EventLabelPurchased || delivery.Attempt != 1 {
+		t.Errorf("delivery = %+v", delivery)
+	}
+	event, verifyErr := rc.last(t)
+	if verifyErr != nil {
+		t.Errorf("test event did not verify: %v", verifyErr)
+	}
+	if event.ID != delivery.EventID || event.Type != atoship.EventLabelPurchased {
+		t.Errorf("received event = %+v, want %s %s", event, delivery.EventID, atoship.EventLabelPurchased)
+	}
+
+	secret, err := client.Webhooks.RotateSecret(ctx, wh.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret == "" || secret == wh.Secret {
+		t.Fatalf("RotateSecret = %q, want a new secret", secret)
+	}
+
+	// Deliveries are signed with the new secret only
+	if _, err := client.Webhooks.SendTest(ctx, wh.ID, atoship.EventOrderCreated); err != nil {
+		t.Fatal(err)
+	}
+	if _, verifyErr := rc.last(t); verifyErr == nil {
+		t.Error("delivery after rotating verified with the old secret")
+	}
+	rc.setSecret(secret)
+	if _, err := client.Webhooks.SendTest(ctx, wh.ID, atoship.EventOrderCreated); err != nil {
+		t.Fatal(err)
+	}
+	if _, verifyErr := rc.last(t); verifyErr != nil {
+		t.Errorf("delivery after rotating did not verify with the new secret: %v", verifyErr)
+	}
+
+	if _, err := client.Webhooks.RotateSecret(ctx, "wh_missing"); !errors.Is(err, atoship.ErrNotFound) {
+		t.Errorf("RotateSecret of a missing webhook: err = %v, want ErrNotFound", err)
+	}
+	if _, err := client.Webhooks.SendTest(ctx, wh.ID, "invoice.paid"); !errors.Is(err, atoship.ErrValidation) {
+		t.Errorf("SendTest of an unknown event type: err = %v, want ErrValidation", err)
+	}
+}
+
+func TestWebhooksRetryDelivery(t *testing.T) {
+	ctx := context.Background()
+	client, srv := atoshiptest.NewClient()
+	defer srv.Close()
+	rc := newReceiver(t)
+	wh := createWebhook(t, client, rc)
+
+	rc.status.Store(http.StatusInternalServerError)
+	failed, err := client.Webhooks.SendTest(ctx, wh.ID, atoship.EventOrderCreated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if failed.Success || failed.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("delivery = %+v, want a failure", failed)
+	}
+
+	rc.status.Store(http.StatusOK)
+	retried, err := client.Webhooks.RetryDelivery(ctx, wh.ID, failed.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !retried.Success || retried.ID == failed.ID || retried.EventID != failed.EventID || retried.Attempt != 2 {
+		t.Errorf("retried delivery = %+v, want attempt 2 of %s", retried, failed.EventID)
+	}
+	if event, _ := rc.last(t); event.ID != failed.EventID {
+		t.Errorf("redelivered event %s, want %s", event.ID, failed.EventID)
+	}
+
+	if _, err := client.Webhooks.RetryDelivery(ctx, wh.ID, "whd_missing"); !errors.Is(err, atoship.ErrNotFound) {
+		t.Errorf("RetryDelivery of a missing delivery: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestWebhooksListDeliveries(t *testing.T) {
+	ctx := context.Background()
+	client, srv := atoshiptest.NewClient()
+	defer srv.Close()
+	wh := createWebhook(t, client, newReceiver(t))
+	other := createWebhook(t, client, newReceiver(t))
+
+	// More than the fake's default page of 20
+	const n = 25
+	var sent []string
+	for i := 0; i < n; i++ {
+		d, err := client.Webhooks.SendTest(ctx, wh.ID, atoship.EventOrderCreated)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sent = append(sent, d.ID)
+	}
+	if _, err := client.Webhooks.SendTest(ctx, other.ID, atoship.EventOrderCreated); err != nil {
+		t.Fatal(err)
+	}
+	slices.Reverse(sent)
+
+	deliveryIDs := func(deliveries []atoship.WebhookDelivery) []string {
+		var ids []string
+		for _, d := range deliveries {
+			ids = append(ids, d.ID)
+		}
+		return ids
+	}
+	countPages := func() int {
+		pages := 0
+		for _, r := range srv.Requests() {
+			if r.Method == http.MethodGet && r.Path == "/api/admin/webhooks/"+wh.ID+"/deliveries" {
+				pages++
+			}
+		}
+		return pages
+	}
+
+	deliveries, err := client.Webhooks.ListDeliveries(ctx, wh.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := deliveryIDs(deliveries); !slices.Equal(got, sent) {
+		t.Errorf("ListDeliveries = %v, want the %d deliveries most recent first %v", got, n, sent)
+	}
+	if pages := countPages(); pages != 2 {
+		t.Errorf("ListDeliveries read %d pages, want 2", pages)
+	}
+
+	pager := client.Webhooks.ListDeliveriesPager(wh.ID, &atoship.ListOptions{Limit: 10})
+	var sizes []int
+	var paged []atoship.WebhookDelivery
+	for pager.More() {
+		page, err := pager.Next(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sizes = append(sizes, len(page.Items))
+		paged = append(paged, page.Items...)
+	}
+	if !slices.Equal(sizes, []int{10, 10, 5}) {
+		t.Errorf("page sizes = %v, want [10 10 5]", sizes)
+	}
+	if got := deliveryIDs(paged); !slices.Equal(got, sent) {
+		t.Errorf("paged deliveries = %v, want %v", got, sent)
+	}
+
+	if _, err := client.Webhooks.ListDeliveries(ctx, "wh_missing"); !errors.Is(err, atoship.ErrNotFound) {
+		t.Errorf("ListDeliveries of a missing webhook: err = %v, want ErrNotFound", err)
+	}
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+// errInvalidAddress reports an address that failed validation
+var errInvalidAddress = &atoship.APIError{
+	Code:    atoship.ErrCodeValidation,
+	Message: "address is not valid",
+}
+
+func (c *cli) addressesValidate(args []string) error {
+	fs, g := c.newFlagSet("atoship addresses validate", "")
+	var req atoship.ValidateAddressRequest
+	fs.StringVar(&req.Name, "name", "", "recipient name")
+	fs.StringVar(&req.Company, "company", "", "company")
+	fs.StringVar(&req.Street1, "street1", "", "street address")
+	fs.StringVar(&req.Street2, "street2", "", "apartment, suite or unit")
+	fs.StringVar(&req.City, "city", "", "city")
+	fs.StringVar(&req.State, "state", "", "state or province")
+	fs.StringVar(&req.PostalCode, "postal-code", "", "postal code")
+	fs.StringVar(&req.Country, "country", "US", "country code")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 0 {
+		return usageError(fs, "unexpected arguments")
+	}
+	if req.Street1 == "" || req.City == "" || req.PostalCode == "" {
+		return usageError(fs, "--street1, --city and --postal-code are required")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	resp, err := client.Addresses.Validate(c.ctx, &req)
+	if err != nil {
+		return err
+	}
+
+	t := &table{headers: []string{"RESULT", "NAME", "STREET", "CITY", "STATE", "POSTAL CODE", "COUNTRY"}}
+	result := "invalid"
+	if resp.IsValid {
+		result = "valid"
+	}
+	if resp.Address != nil {
+		t.add(addressRow(result, resp.Address)...)
+	} else {
+		t.add(result, req.Name, req.Street1, req.City, req.State, req.PostalCode, req.Country)
+	}
+	for i := range resp.Suggestions {
+		t.add(addressRow("suggestion", &resp.Suggestions[i])...)
+	}
+	if err := g.print(c.stdout, resp, t); err != nil {
+		return err
+	}
+
+	if !resp.IsValid {
+		if len(resp.Errors) > 0 {
+			return errors.Join(errInvalidAddress, errors.New(strings.Join(resp.Errors, "; ")))
+		}
+		return errInvalidAddress
+	}
+	return nil
+}
+
+func addressRow(result string, a *atoship.Address) []string {
+	return []string{result, a.Name, strings.Join(nonEmpty(a.Street1, a.Street2), ", "), a.City, a.State, a.PostalCode, a.Country}
+}
@@ -0,0 +1,29 @@
+package main
+
+import "strconv"
+
+func (c *cli) adminStats(args []string) error {
+	fs, g := c.newFlagSet("atoship admin stats", "")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 0 {
+		return usageError(fs, "unexpected arguments")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	stats, err := client.Admin.GetStats(c.ctx)
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, stats, keyValues(
+		"Orders", strconv.Itoa(stats.TotalOrders),
+		"Shipments", strconv.Itoa(stats.TotalShipments),
+		"Revenue", formatMoney(stats.TotalRevenue),
+		"Active users", strconv.Itoa(stats.ActiveUsers),
+	))
+}
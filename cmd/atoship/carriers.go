@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+func (c *cli) carriersList(args []string) error {
+	fs, g := c.newFlagSet("atoship carriers list", "")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 0 {
+		return usageError(fs, "unexpected arguments")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	carriers, err := client.Carriers.List(c.ctx)
+	if err != nil {
+		return err
+	}
+	if carriers == nil {
+		carriers = []atoship.Carrier{}
+	}
+
+	t := &table{headers: []string{"ID", "CODE", "NAME", "ACTIVE", "SERVICES"}}
+	for _, carrier := range carriers {
+		t.add(carrier.ID, carrier.Code, carrier.Name, formatBool(carrier.Active), strings.Join(carrier.Services, ", "))
+	}
+	return g.print(c.stdout, carriers, t)
+}
@@ -0,0 +1,309 @@
+// Command atoship is a command-line client for the atoship API.
+//
+// Usage:
+//
+//	atoship <command> [subcommand] [flags] [arguments]
+//
+// Settings are taken from flags, ATOSHIP_* environment variables and the
+// selected --profile of ~/.config/atoship/config, in that order; see
+// atoship.LoadConfig. Commands that spend money, such as "labels buy", are
+// refused for live API keys unless --live is passed or the profile sets
+// environment = live. Results are printed as a table, JSON or CSV according to
+// --output.
+//
+// The exit status is 0 on success, 2 for invalid usage, and otherwise
+// reflects the APIError code of a failed request:
+//
+//	1  any other error
+//	3  CONFIGURATION_ERROR
+//	4  AUTHENTICATION_ERROR or AUTHORIZATION_ERROR
+//	5  NOT_FOUND
+//	6  VALIDATION_ERROR
+//	7  RATE_LIMIT_EXCEEDED
+//	8  SERVER_ERROR
+//	9  NETWORK_ERROR or TIMEOUT_ERROR
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+// Exit codes
+const (
+	exitOK         = 0
+	exitError      = 1
+	exitUsage      = 2
+	exitConfig     = 3
+	exitAuth       = 4
+	exitNotFound   = 5
+	exitValidation = 6
+	exitRateLimit  = 7
+	exitServer     = 8
+	exitNetwork    = 9
+)
+
+// errUsage reports invalid command-line arguments
+var errUsage = errors.New("invalid usage")
+
+// command is a command or group of subcommands
+type command struct {
+	name    string
+	summary string
+	run     func(c *cli, args []string) error
+	sub     []command
+}
+
+// commands is the command tree
+var commands = []command{
+	{name: "orders", summary: "Manage orders", sub: []command{
+		{name: "list", summary: "List orders", run: (*cli).ordersList},
+		{name: "get", summary: "Show an order", run: (*cli).ordersGet},
+		{name: "create", summary: "Create orders from JSON", run: (*cli).ordersCreate},
+		{name: "cancel", summary: "Cancel an order", run: (*cli).ordersCancel},
+		{name: "ship", summary: "Mark an order as shipped", run: (*cli).ordersShip},
+	}},
+	{name: "rates", summary: "Quote shipping rates", sub: []command{
+		{name: "get", summary: "Get rates for a parcel", run: (*cli).ratesGet},
+	}},
+	{name: "labels", summary: "Manage shipping labels", sub: []command{
+		{name: "buy", summary: "Purchase a label for a rate", run: (*cli).labelsBuy},
+		{name: "get", summary: "Show a label", run: (*cli).labelsGet},
+		{name: "void", summary: "Void a label", run: (*cli).labelsVoid},
+		{name: "download", summary: "Save a label's PDF", run: (*cli).labelsDownload},
+	}},
+	{name: "track", summary: "Track packages", run: (*cli).track},
+	{name: "addresses", summary: "Work with addresses", sub: []command{
+		{name: "validate", summary: "Validate an address", run: (*cli).addressesValidate},
+	}},
+	{name: "webhooks", summary: "Manage webhooks", sub: []command{
+		{name: "list", summary: "List webhooks", run: (*cli).webhooksList},
+		{name: "get", summary: "Show a webhook", run: (*cli).webhooksGet},
+		{name: "create", summary: "Create a webhook", run: (*cli).webhooksCreate},
+		{name: "update", summary: "Change a webhook's URL or events", run: (*cli).webhooksUpdate},
+		{name: "enable", summary: "Resume deliveries to a webhook", run: (*cli).webhooksEnable},
+		{name: "disable", summary: "Pause deliveries to a webhook", run: (*cli).webhooksDisable},
+		{name: "delete", summary: "Delete a webhook", run: (*cli).webhooksDelete},
+		{name: "rotate-secret", summary: "Replace a webhook's signing secret", run: (*cli).webhooksRotateSecret},
+		{name: "test", summary: "Send a sample event", run: (*cli).webhooksTest},
+		{name: "deliveries", summary: "List delivery attempts", run: (*cli).webhooksDeliveries},
+		{name: "retry", summary: "Redeliver an event", run: (*cli).webhooksRetry},
+		{name: "listen", summary: "Forward deliveries to a local handler", run: (*cli).webhooksListen},
+	}},
+	{name: "carriers", summary: "List carriers", sub: []command{
+		{name: "list", summary: "List available carriers", run: (*cli).carriersList},
+	}},
+	{name: "admin", summary: "Account administration", sub: []command{
+		{name: "stats", summary: "Show account statistics", run: (*cli).adminStats},
+	}},
+}
+
+// cli holds the state shared by every command
+type cli struct {
+	ctx    context.Context
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	code := run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	stop()
+	os.Exit(code)
+}
+
+// run executes the command in args and returns the process exit code
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	c := &cli{ctx: ctx, stdin: stdin, stdout: stdout, stderr: stderr}
+	err := c.dispatch("atoship", commands, args)
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.Is(err, errUsage):
+		return exitUsage
+	default:
+		fmt.Fprintf(stderr, "atoship: %v\n", err)
+		return exitCode(err)
+	}
+}
+
+// dispatch runs the command named by args[0] among cmds
+func (c *cli) dispatch(path string, cmds []command, args []string) error {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "-help" || args[0] == "--help" {
+		printCommands(c.stderr, path, cmds)
+		if len(args) == 0 {
+			return errUsage
+		}
+		return flag.ErrHelp
+	}
+
+	for _, cmd := range cmds {
+		if cmd.name != args[0] {
+			continue
+		}
+		if cmd.run != nil {
+			return cmd.run(c, args[1:])
+		}
+		return c.dispatch(path+" "+cmd.name, cmd.sub, args[1:])
+	}
+
+	fmt.Fprintf(c.stderr, "%s: unknown command %q\n\n", path, args[0])
+	printCommands(c.stderr, path, cmds)
+	return errUsage
+}
+
+// printCommands prints the usage of a command group
+func printCommands(w io.Writer, path string, cmds []command) {
+	fmt.Fprintf(w, "Usage: %s <command> [flags] [arguments]\n\nCommands:\n", path)
+	for _, cmd := range cmds {
+		fmt.Fprintf(w, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintf(w, "\nRun \"%s <command> -h\" for more information.\n", path)
+}
+
+// exitCode maps an error to the process exit code
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, atoship.ErrConfig):
+		return exitConfig
+	case errors.Is(err, atoship.ErrUnauthorized), errors.Is(err, atoship.ErrForbidden):
+		return exitAuth
+	case errors.Is(err, atoship.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, atoship.ErrValidation):
+		return exitValidation
+	case errors.Is(err, atoship.ErrRateLimited):
+		return exitRateLimit
+	case errors.Is(err, atoship.ErrServer):
+		return exitServer
+	case errors.Is(err, atoship.ErrNetwork), errors.Is(err, atoship.ErrTimeout):
+		return exitNetwork
+	default:
+		return exitError
+	}
+}
+
+// globalFlags are the flags shared by every command
+type globalFlags struct {
+	apiKey     string
+	baseURL    string
+	configPath string
+	profile    string
+	output     string
+	live       bool
+}
+
+// newFlagSet returns a flag set for a command that takes the shared flags.
+// args describes the positional arguments in the usage message.
+func (c *cli) newFlagSet(name, args string) (*flag.FlagSet, *globalFlags) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: %s [flags] %s\n\nFlags:\n", name, args)
+		fs.PrintDefaults()
+	}
+
+	g := &globalFlags{}
+	fs.StringVar(&g.apiKey, "api-key", "", "API key (default $ATOSHIP_API_KEY)")
+	fs.StringVar(&g.baseURL, "base-url", "", "API base URL (default $ATOSHIP_BASE_URL or "+atoship.DefaultBaseURL+")")
+	fs.StringVar(&g.configPath, "config", "", "config file (default $ATOSHIP_CONFIG_FILE or ~/.config/atoship/config)")
+	fs.StringVar(&g.profile, "profile", "", "config file profile (default $ATOSHIP_PROFILE or default)")
+	fs.StringVar(&g.output, "output", "table", "output format: table, json or csv")
+	fs.BoolVar(&g.live, "live", false, "allow calls that spend money with a live API key")
+	return fs, g
+}
+
+// parseFlags parses args into fs, allowing flags to follow positional
+// arguments, and returns the positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, errUsage
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+// usageError prints the usage of fs with a message and returns errUsage
+func usageError(fs *flag.FlagSet, format string, args ...any) error {
+	fmt.Fprintf(fs.Output(), "%s: %s\n\n", fs.Name(), fmt.Sprintf(format, args...))
+	fs.Usage()
+	return errUsage
+}
+
+// client returns an API client configured from the flags, environment and
+// config file
+func (g *globalFlags) client() (*atoship.Client, error) {
+	if !formats[g.output] {
+		return nil, configError("unknown output format %q", g.output)
+	}
+
+	var cfg *atoship.Config
+	var err error
+	if g.configPath != "" {
+		cfg, err = atoship.LoadConfigFile(g.configPath, g.profile)
+	} else {
+		cfg, err = atoship.LoadConfig(g.profile)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if g.apiKey != "" {
+		cfg.APIKey = g.apiKey
+	}
+	if g.baseURL != "" {
+		cfg.BaseURL = g.baseURL
+	}
+	if g.live {
+		cfg.Environment = atoship.EnvironmentLive
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 2
+	}
+	cfg.LiveGuard = true
+	return atoship.NewClientFromConfig(cfg)
+}
+
+// readInput reads the named file, or stdin for "-"
+func (c *cli) readInput(name string) ([]byte, error) {
+	if name == "-" {
+		return io.ReadAll(c.stdin)
+	}
+	return os.ReadFile(name)
+}
+
+// configError returns an APIError with the configuration error code
+func configError(format string, args ...any) error {
+	return &atoship.APIError{
+		Code:    atoship.ErrCodeConfigError,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// firstNonEmpty returns the first non-empty value
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
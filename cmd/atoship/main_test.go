@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+	"github.com/atoship-LLC/atoship-go/atoship/atoshiptest"
+)
+
+// isolate keeps the environment and any config file in the user's home
+// directory out of a test
+func isolate(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{
+		atoship.EnvAPIKey, atoship.EnvBaseURL, atoship.EnvTimeout, atoship.EnvMaxRetries, atoship.EnvDebug,
+		atoship.EnvEnvironment, atoship.EnvLiveGuard, atoship.EnvProfile, atoship.EnvConfigFile, "ATOSHIP_WEBHOOK_SECRET",
+	} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+}
+
+// runCLI runs the CLI against srv with the server's API key and returns
+// the exit code and output
+func runCLI(t *testing.T, srv *atoshiptest.Server, stdin string, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+	args = append(args, "--base-url", srv.URL)
+	if !hasFlag(args, "--api-key") {
+		args = append(args, "--api-key", srv.APIKey)
+	}
+	var out, errOut bytes.Buffer
+	code = run(context.Background(), args, strings.NewReader(stdin), &out, &errOut)
+	return code, out.String(), errOut.String()
+}
+
+func hasFlag(args []string, name string) bool {
+	for _, arg := range args {
+		if arg == name || strings.HasPrefix(arg, name+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+const testOrder = `{"orderNumber":"ORD-1","recipientName":"Jane Doe","recipientStreet1":"1 Main St","recipientCity":"Austin","recipientState":"TX","recipientPostalCode":"78701","recipientCountry":"US","items":[{"name":"Widget","quantity":1,"unitPrice":9.99}]}`
+
+func TestExitCodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		failure *atoshiptest.Failure
+		stdin   string
+		args    []string
+		want    int
+	}{
+		{name: "success", args: []string{"carriers", "list"}, want: exitOK},
+		{name: "not found", args: []string{"orders", "get", "ord_missing"}, want: exitNotFound},
+		{name: "unauthorized", args: []string{"carriers", "list", "--api-key", "test_wrong"}, want: exitAuth},
+		{
+			name:    "forbidden",
+			failure: &atoshiptest.Failure{Status: http.StatusForbidden},
+			args:    []string{"carriers", "list"},
+			want:    exitAuth,
+		},
+		{name: "validation", stdin: `{}`, args: []string{"orders", "create"}, want: exitValidation},
+		{
+			name:    "rate limited",
+			failure: &atoshiptest.Failure{Status: http.StatusTooManyRequests},
+			args:    []string{"carriers", "list"},
+			want:    exitRateLimit,
+		},
+		{
+			name:    "server error",
+			failure: &atoshiptest.Failure{Status: http.StatusNotImplemented},
+			args:    []string{"carriers", "list"},
+			want:    exitServer,
+		},
+		{
+			name:    "network error",
+			failure: &atoshiptest.Failure{Drop: true},
+			args:    []string{"carriers", "list"},
+			want:    exitNetwork,
+		},
+		{name: "unknown output format", args: []string{"carriers", "list", "--output", "yaml"}, want: exitConfig},
+		{name: "missing config file", args: []string{"carriers", "list", "--config", "/nonexistent/atoship"}, want: exitConfig},
+		{name: "malformed input", stdin: `{`, args: []string{"orders", "create"}, want: exitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t)
+			srv := atoshiptest.NewServer()
+			defer srv.Close()
+			if tt.failure != nil {
+				srv.InjectFailure(*tt.failure)
+			}
+
+			code, _, stderr := runCLI(t, srv, tt.stdin, tt.args...)
+			if code != tt.want {
+				t.Errorf("exit code = %d, want %d; stderr:\n%s", code, tt.want, stderr)
+			}
+			if code != exitOK && !strings.HasPrefix(stderr, "atoship: ") {
+				t.Errorf("stderr = %q, want an error message", stderr)
+			}
+		})
+	}
+}
+
+func TestUsageErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want int
+		msg  string
+	}{
+		{"no arguments", nil, exitUsage, "Usage: atoship <command>"},
+		{"help", []string{"help"}, exitOK, "Usage: atoship <command>"},
+		{"unknown command", []string{"shipments"}, exitUsage, `unknown command "shipments"`},
+		{"unknown subcommand", []string{"orders", "archive"}, exitUsage, `atoship orders: unknown command "archive"`},
+		{"command help", []string{"orders", "get", "-h"}, exitOK, "Usage: atoship orders get [flags] ORDER_ID"},
+		{"missing argument", []string{"orders", "get"}, exitUsage, "expected an order ID"},
+		{"extra argument", []string{"orders", "get", "ord_1", "ord_2"}, exitUsage, "expected an order ID"},
+		{"unknown flag", []string{"orders", "list", "--color"}, exitUsage, "flag provided but not defined: -color"},
+		{"missing required flags", []string{"orders", "ship", "ord_1"}, exitUsage, "--tracking-number and --carrier are required"},
+		{"listen without a source", []string{"webhooks", "listen", "--forward-to", "http://localhost:3000"}, exitUsage, "Usage: atoship webhooks listen"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolate(t)
+			var stdout, stderr bytes.Buffer
+			code := run(context.Background(), tt.args, strings.NewReader(""), &stdout, &stderr)
+			if code != tt.want {
+				t.Errorf("exit code = %d, want %d", code, tt.want)
+			}
+			if !strings.Contains(stderr.String(), tt.msg) {
+				t.Errorf("stderr does not contain %q:\n%s", tt.msg, stderr.String())
+			}
+			if stdout.Len() != 0 {
+				t.Errorf("stdout = %q, want nothing", stdout.String())
+			}
+		})
+	}
+}
+
+func TestOutputFormats(t *testing.T) {
+	isolate(t)
+	srv := atoshiptest.NewServer()
+	defer srv.Close()
+	if code, _, stderr := runCLI(t, srv, testOrder, "orders", "create"); code != exitOK {
+		t.Fatalf("orders create: exit code %d; stderr:\n%s", code, stderr)
+	}
+	id := srv.Orders()[0].ID
+
+	t.Run("table", func(t *testing.T) {
+		code, stdout, _ := runCLI(t, srv, "", "orders", "list")
+		if code != exitOK {
+			t.Fatalf("exit code = %d", code)
+		}
+		lines := strings.Split(strings.TrimRight(stdout, "\n"), "\n")
+		if len(lines) != 2 {
+			t.Fatalf("table has %d lines, want a header and one row:\n%s", len(lines), stdout)
+		}
+		if fields := strings.Fields(lines[0]); fields[0] != "ID" {
+			t.Errorf("header = %q", lines[0])
+		}
+		if !strings.HasPrefix(lines[1], id+" ") || !strings.Contains(lines[1], "ORD-1") || !strings.Contains(lines[1], "Jane Doe") {
+			t.Errorf("row = %q", lines[1])
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		code, stdout, _ := runCLI(t, srv, "", "orders", "get", id, "--output", "json")
+		if code != exitOK {
+			t.Fatalf("exit code = %d", code)
+		}
+		var order atoship.Order
+		if err := json.Unmarshal([]byte(stdout), &order); err != nil {
+			t.Fatalf("output is not JSON: %v\n%s", err, stdout)
+		}
+		if order.ID != id || order.OrderNumber != "ORD-1" || order.RecipientName != "Jane Doe" {
+			t.Errorf("order = %+v", order)
+		}
+	})
+
+	t.Run("json empty list", func(t *testing.T) {
+		code, stdout, _ := runCLI(t, srv, "", "webhooks", "list", "--output=json")
+		if code != exitOK || strings.TrimSpace(stdout) != "[]" {
+			t.Errorf("exit code = %d, output = %q; want an empty JSON array", code, stdout)
+		}
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		code, stdout, _ := runCLI(t, srv, "", "carriers", "list", "--output", "csv")
+		if code != exitOK {
+			t.Fatalf("exit code = %d", code)
+		}
+		records, err := csv.NewReader(strings.NewReader(stdout)).ReadAll()
+		if err != nil {
+			t.Fatalf("output is not CSV: %v\n%s", err, stdout)
+		}
+		if len(records) != 4 || records[0][0] != "ID" || records[1][0] != "car_usps" {
+			t.Errorf("records = %q", records)
+		}
+	})
+}
+
+func TestLiveGuard(t *testing.T) {
+	isolate(t)
+	srv := atoshiptest.NewServer()
+	defer srv.Close()
+	srv.APIKey = "live_atoship_key"
+
+	code, stdout, stderr := runCLI(t, srv, "", "rates", "get", "--from-postal", "10001", "--to-postal", "94105", "--weight", "2", "--output", "json")
+	if code != exitOK {
+		t.Fatalf("rates get: exit code %d; stderr:\n%s", code, stderr)
+	}
+	var rates []atoship.ShippingRate
+	if err := json.Unmarshal([]byte(stdout), &rates); err != nil || len(rates) == 0 {
+		t.Fatalf("rates = %s, err = %v", stdout, err)
+	}
+	rateID := rates[0].ID
+
+	code, _, stderr = runCLI(t, srv, "", "labels", "buy", rateID)
+	if code != exitConfig {
+		t.Errorf("labels buy without --live: exit code = %d, want %d", code, exitConfig)
+	}
+	if !strings.Contains(stderr, "live key") {
+		t.Errorf("stderr = %q, want the live guard's refusal", stderr)
+	}
+	if len(srv.Labels()) != 0 {
+		t.Fatal("labels buy without --live purchased a label")
+	}
+	for _, r := range srv.Requests() {
+		if strings.HasPrefix(r.Path, "/api/labels") {
+			t.Errorf("labels buy without --live sent %s %s", r.Method, r.Path)
+		}
+	}
+
+	code, _, stderr = runCLI(t, srv, "", "labels", "buy", rateID, "--live")
+	if code != exitOK {
+		t.Fatalf("labels buy --live: exit code = %d; stderr:\n%s", code, stderr)
+	}
+	if len(srv.Labels()) != 1 {
+		t.Errorf("labels buy --live purchased %d labels, want 1", len(srv.Labels()))
+	}
+}
+
+func TestLabelsDownload(t *testing.T) {
+	isolate(t)
+	srv := atoshiptest.NewServer()
+	defer srv.Close()
+
+	code, stdout, stderr := runCLI(t, srv, "", "rates", "get", "--from-postal", "10001", "--to-postal", "94105", "--weight", "2", "--output", "json")
+	if code != exitOK {
+		t.Fatalf("rates get: exit code %d; stderr:\n%s", code, stderr)
+	}
+	var rates []atoship.ShippingRate
+	if err := json.Unmarshal([]byte(stdout), &rates); err != nil || len(rates) == 0 {
+		t.Fatalf("rates = %s, err = %v", stdout, err)
+	}
+	if code, _, stderr := runCLI(t, srv, "", "labels", "buy", rates[0].ID); code != exitOK {
+		t.Fatalf("labels buy: exit code %d; stderr:\n%s", code, stderr)
+	}
+	label := srv.Labels()[0]
+
+	out := filepath.Join(t.TempDir(), "label.pdf")
+	code, _, stderr = runCLI(t, srv, "", "labels", "download", label.ID, "--out", out)
+	if code != exitOK {
+		t.Fatalf("labels download: exit code %d; stderr:\n%s", code, stderr)
+	}
+	pdf, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(pdf, []byte("%PDF-")) || !bytes.Contains(pdf, []byte(label.TrackingNumber)) {
+		t.Errorf("downloaded %q, want the label PDF", pdf)
+	}
+
+	if code, _, _ := runCLI(t, srv, "", "labels", "download", "lbl_missing", "--out", out); code != exitNotFound {
+		t.Errorf("missing label: exit code = %d, want %d", code, exitNotFound)
+	}
+}
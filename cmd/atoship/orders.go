@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+var orderHeaders = []string{"ID", "NUMBER", "STATUS", "RECIPIENT", "CITY", "COUNTRY", "TOTAL", "TRACKING", "CREATED"}
+
+func orderRow(o *atoship.Order) []string {
+	return []string{
+		o.ID,
+		o.OrderNumber,
+		o.Status,
+		o.RecipientName,
+		o.RecipientCity,
+		o.RecipientCountry,
+		formatMoney(o.TotalValue),
+		o.TrackingNumber,
+		formatTime(o.CreatedAt),
+	}
+}
+
+func orderTable(orders ...atoship.Order) *table {
+	t := &table{headers: orderHeaders}
+	for i := range orders {
+		t.add(orderRow(&orders[i])...)
+	}
+	return t
+}
+
+func (c *cli) ordersList(args []string) error {
+	fs, g := c.newFlagSet("atoship orders list", "")
+	var opts atoship.ListOrdersOptions
+	fs.StringVar(&opts.Status, "status", "", "only orders with this status")
+	fs.StringVar(&opts.Source, "source", "", "only orders from this source")
+	fs.StringVar(&opts.Search, "search", "", "search order numbers and recipients")
+	fs.StringVar(&opts.StartDate, "start-date", "", "only orders created on or after this date")
+	fs.StringVar(&opts.EndDate, "end-date", "", "only orders created on or before this date")
+	fs.IntVar(&opts.Page, "page", 1, "page to show")
+	fs.IntVar(&opts.Limit, "limit", 20, "orders per page")
+	all := fs.Bool("all", false, "list every matching order, starting at --page")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 0 {
+		return usageError(fs, "unexpected arguments")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	var orders []atoship.Order
+	if *all {
+		orders, err = client.Orders.ListPager(&opts).All(c.ctx)
+	} else {
+		var resp *atoship.OrderListResponse
+		resp, err = client.Orders.List(c.ctx, &opts)
+		if resp != nil {
+			orders = resp.Orders
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if orders == nil {
+		orders = []atoship.Order{}
+	}
+	return g.print(c.stdout, orders, orderTable(orders...))
+}
+
+func (c *cli) ordersGet(args []string) error {
+	fs, g := c.newFlagSet("atoship orders get", "ORDER_ID")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageError(fs, "expected an order ID")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	order, err := client.Orders.Get(c.ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, order, orderDetail(order))
+}
+
+// orderDetail returns the key fields of one order
+func orderDetail(o *atoship.Order) *table {
+	return keyValues(
+		"ID", o.ID,
+		"Number", o.OrderNumber,
+		"Status", o.Status,
+		"Source", o.Source,
+		"Recipient", o.RecipientName,
+		"Address", strings.Join(nonEmpty(o.RecipientStreet1, o.RecipientStreet2, o.RecipientCity, o.RecipientState, o.RecipientPostal, o.RecipientCountry), ", "),
+		"Items", strconv.Itoa(len(o.Items)),
+		"Weight", formatFloat(o.TotalWeight)+" "+o.WeightUnit,
+		"Total", formatMoney(o.TotalValue)+" "+o.Currency,
+		"Tracking", o.TrackingNumber,
+		"Created", formatTime(o.CreatedAt),
+		"Updated", formatTime(o.UpdatedAt),
+	)
+}
+
+func (c *cli) ordersCreate(args []string) error {
+	fs, g := c.newFlagSet("atoship orders create", "")
+	file := fs.String("file", "-", "JSON order, or array of orders, to create; - reads stdin")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 0 {
+		return usageError(fs, "unexpected arguments")
+	}
+
+	raw, err := c.readInput(*file)
+	if err != nil {
+		return err
+	}
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+		var reqs []*atoship.CreateOrderRequest
+		if err := json.Unmarshal(raw, &reqs); err != nil {
+			return fmt.Errorf("parsing %s: %w", *file, err)
+		}
+		resp, err := client.Orders.BulkCreate(c.ctx, reqs)
+		if err != nil {
+			return err
+		}
+		if err := g.print(c.stdout, resp, orderTable(resp.Successful...)); err != nil {
+			return err
+		}
+		for _, failed := range resp.Failed {
+			fmt.Fprintf(c.stderr, "order %s failed: %s\n", failed.Order.OrderNumber, failed.Error)
+		}
+		if len(resp.Failed) > 0 {
+			return fmt.Errorf("%d of %d orders failed", len(resp.Failed), len(reqs))
+		}
+		return nil
+	}
+
+	var req atoship.CreateOrderRequest
+	if err := json.Unmarshal(raw, &req); err != nil {
+		return fmt.Errorf("parsing %s: %w", *file, err)
+	}
+	order, err := client.Orders.Create(c.ctx, &req)
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, order, orderDetail(order))
+}
+
+func (c *cli) ordersCancel(args []string) error {
+	fs, g := c.newFlagSet("atoship orders cancel", "ORDER_ID")
+	reason := fs.String("reason", "", "reason for cancelling")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageError(fs, "expected an order ID")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	order, err := client.Orders.Cancel(c.ctx, pos[0], *reason)
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, order, orderDetail(order))
+}
+
+func (c *cli) ordersShip(args []string) error {
+	fs, g := c.newFlagSet("atoship orders ship", "ORDER_ID")
+	trackingNumber := fs.String("tracking-number", "", "tracking number of the shipment (required)")
+	carrier := fs.String("carrier", "", "carrier of the shipment (required)")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageError(fs, "expected an order ID")
+	}
+	if *trackingNumber == "" || *carrier == "" {
+		return usageError(fs, "--tracking-number and --carrier are required")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	order, err := client.Orders.Ship(c.ctx, pos[0], *trackingNumber, *carrier)
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, order, orderDetail(order))
+}
+
+// nonEmpty returns the non-empty values
+func nonEmpty(values ...string) []string {
+	var out []string
+	for _, v := range values {
+		if v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
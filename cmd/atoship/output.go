@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// formats are the supported --output values
+var formats = map[string]bool{"table": true, "json": true, "csv": true}
+
+// table is the tabular form of a result
+type table struct {
+	headers []string
+	rows    [][]string
+}
+
+// add appends a row
+func (t *table) add(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// print writes v as JSON, or tbl as a table or CSV, according to the
+// --output flag
+func (g *globalFlags) print(w io.Writer, v any, tbl *table) error {
+	switch g.output {
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case "csv":
+		cw := csv.NewWriter(w)
+		cw.Write(tbl.headers)
+		cw.WriteAll(tbl.rows)
+		return cw.Error()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.Join(tbl.headers, "\t"))
+		for _, row := range tbl.rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+// keyValues returns a two-column table of field names and values, used for
+// single records
+func keyValues(pairs ...string) *table {
+	t := &table{headers: []string{"FIELD", "VALUE"}}
+	for i := 0; i+1 < len(pairs); i += 2 {
+		t.add(pairs[i], pairs[i+1])
+	}
+	return t
+}
+
+func formatMoney(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func formatBool(b bool) string {
+	return strconv.FormatBool(b)
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+func (c *cli) ratesGet(args []string) error {
+	fs, g := c.newFlagSet("atoship rates get", "")
+	file := fs.String("file", "", "JSON rate request to send instead of the address and parcel flags; - reads stdin")
+	from := atoship.Address{}
+	to := atoship.Address{}
+	parcel := atoship.Parcel{}
+	fs.StringVar(&from.PostalCode, "from-postal", "", "origin postal code")
+	fs.StringVar(&from.State, "from-state", "", "origin state")
+	fs.StringVar(&from.Country, "from-country", "US", "origin country")
+	fs.StringVar(&to.PostalCode, "to-postal", "", "destination postal code")
+	fs.StringVar(&to.State, "to-state", "", "destination state")
+	fs.StringVar(&to.Country, "to-country", "US", "destination country")
+	fs.Float64Var(&parcel.Weight, "weight", 0, "parcel weight")
+	fs.StringVar(&parcel.WeightUnit, "weight-unit", "lb", "unit of --weight")
+	fs.Float64Var(&parcel.Length, "length", 0, "parcel length")
+	fs.Float64Var(&parcel.Width, "width", 0, "parcel width")
+	fs.Float64Var(&parcel.Height, "height", 0, "parcel height")
+	fs.StringVar(&parcel.DimUnit, "dim-unit", "in", "unit of the parcel dimensions")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 0 {
+		return usageError(fs, "unexpected arguments")
+	}
+
+	req := &atoship.RateRequest{FromAddress: &from, ToAddress: &to, Parcel: &parcel}
+	if *file != "" {
+		raw, err := c.readInput(*file)
+		if err != nil {
+			return err
+		}
+		req = &atoship.RateRequest{}
+		if err := json.Unmarshal(raw, req); err != nil {
+			return fmt.Errorf("parsing %s: %w", *file, err)
+		}
+	} else if from.PostalCode == "" || to.PostalCode == "" || parcel.Weight <= 0 {
+		return usageError(fs, "--from-postal, --to-postal and --weight are required without --file")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	rates, err := client.Shipping.GetRates(c.ctx, req)
+	if err != nil {
+		return err
+	}
+	if rates == nil {
+		rates = []atoship.ShippingRate{}
+	}
+
+	t := &table{headers: []string{"RATE ID", "CARRIER", "SERVICE", "RATE", "CURRENCY", "DAYS"}}
+	for _, r := range rates {
+		days := ""
+		if r.DeliveryDays > 0 {
+			days = strconv.Itoa(r.DeliveryDays)
+		}
+		t.add(r.ID, r.Carrier, r.Service, formatMoney(r.Rate), r.Currency, days)
+	}
+	return g.print(c.stdout, rates, t)
+}
+
+// labelDetail returns the fields of a label, leaving out the PDF
+func labelDetail(l *atoship.ShippingLabel) *table {
+	return keyValues(
+		"ID", l.ID,
+		"Tracking", l.TrackingNumber,
+		"Carrier", l.Carrier,
+		"Service", l.Service,
+		"Rate", formatMoney(l.Rate),
+		"Label URL", l.LabelURL,
+		"Created", formatTime(l.CreatedAt),
+	)
+}
+
+// withoutPDF returns a copy of l without the embedded PDF, which is too
+// large to print
+func withoutPDF(l *atoship.ShippingLabel) *atoship.ShippingLabel {
+	out := *l
+	out.LabelPDF = ""
+	return &out
+}
+
+func (c *cli) labelsBuy(args []string) error {
+	fs, g := c.newFlagSet("atoship labels buy", "RATE_ID")
+	var req atoship.PurchaseLabelRequest
+	fs.StringVar(&req.OrderID, "order", "", "order to attach the label to")
+	fs.StringVar(&req.LabelFormat, "format", "", "label format, such as PDF or ZPL")
+	fs.BoolVar(&req.ReturnLabel, "return", false, "buy a return label")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageError(fs, "expected a rate ID")
+	}
+	req.RateID = pos[0]
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	label, err := client.Shipping.PurchaseLabel(c.ctx, &req)
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, withoutPDF(label), labelDetail(label))
+}
+
+func (c *cli) labelsGet(args []string) error {
+	fs, g := c.newFlagSet("atoship labels get", "LABEL_ID")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageError(fs, "expected a label ID")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	label, err := client.Shipping.GetLabel(c.ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, withoutPDF(label), labelDetail(label))
+}
+
+func (c *cli) labelsVoid(args []string) error {
+	fs, g := c.newFlagSet("atoship labels void", "LABEL_ID")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageError(fs, "expected a label ID")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	label, err := client.Shipping.CancelLabel(c.ctx, pos[0])
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, withoutPDF(label), labelDetail(label))
+}
+
+func (c *cli) labelsDownload(args []string) error {
+	fs, g := c.newFlagSet("atoship labels download", "LABEL_ID")
+	out := fs.String("out", "", "file to write, or - for stdout (default LABEL_ID.pdf)")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageError(fs, "expected a label ID")
+	}
+	if *out == "" {
+		*out = pos[0] + ".pdf"
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	label, err := client.Shipping.GetLabel(c.ctx, pos[0])
+	if err != nil {
+		return err
+	}
+
+	var pdf []byte
+	switch {
+	case label.LabelPDF != "":
+		pdf, err = base64.StdEncoding.DecodeString(label.LabelPDF)
+		if err != nil {
+			return fmt.Errorf("decoding label PDF: %w", err)
+		}
+	case label.LabelURL != "":
+		pdf, err = c.download(label.LabelURL)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("label %s has no PDF", label.ID)
+	}
+
+	if *out == "-" {
+		_, err = c.stdout.Write(pdf)
+		return err
+	}
+	if err := os.WriteFile(*out, pdf, 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(c.stderr, "Saved label %s to %s\n", label.ID, *out)
+	return nil
+}
+
+// download fetches the body of a URL
+func (c *cli) download(url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
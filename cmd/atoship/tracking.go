@@ -0,0 +1,59 @@
+package main
+
+import (
+	"github.com/atoship-LLC/atoship-go/atoship"
+)
+
+func (c *cli) track(args []string) error {
+	fs, g := c.newFlagSet("atoship track", "TRACKING_NUMBER...")
+	carrier := fs.String("carrier", "", "carrier of the package, when known")
+	events := fs.Bool("events", false, "show the tracking history instead of a summary")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) == 0 {
+		return usageError(fs, "expected at least one tracking number")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+
+	var infos []atoship.TrackingInfo
+	switch {
+	case len(pos) > 1:
+		infos, err = client.Tracking.BatchTrack(c.ctx, pos)
+	case *carrier != "":
+		var info *atoship.TrackingInfo
+		info, err = client.Tracking.TrackWithCarrier(c.ctx, pos[0], *carrier)
+		infos = []atoship.TrackingInfo{*info}
+	default:
+		var info *atoship.TrackingInfo
+		info, err = client.Tracking.Track(c.ctx, pos[0])
+		infos = []atoship.TrackingInfo{*info}
+	}
+	if err != nil {
+		return err
+	}
+	if infos == nil {
+		infos = []atoship.TrackingInfo{}
+	}
+
+	if *events {
+		t := &table{headers: []string{"TRACKING", "TIME", "STATUS", "LOCATION", "DESCRIPTION"}}
+		for _, info := range infos {
+			for _, e := range info.Events {
+				t.add(info.TrackingNumber, formatTime(e.Timestamp), e.Status, e.Location, e.Description)
+			}
+		}
+		return g.print(c.stdout, infos, t)
+	}
+
+	t := &table{headers: []string{"TRACKING", "CARRIER", "STATUS", "LOCATION", "DELIVERED", "EXCEPTION"}}
+	for _, info := range infos {
+		t.add(info.TrackingNumber, info.Carrier, info.Status, info.CurrentLocation, formatBool(info.Delivered), info.ExceptionReason)
+	}
+	return g.print(c.stdout, infos, t)
+}
@@ -0,0 +1,523 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/atoship-LLC/atoship-go/atoship"
+	"github.com/atoship-LLC/atoship-go/atoship/webhooks"
+)
+
+// defaultLocalSecret signs forwarded events when no secret is configured
+const defaultLocalSecret = "whsec_local"
+
+func webhookTable(hooks ...atoship.Webhook) *table {
+	t := &table{headers: []string{"ID", "URL", "ACTIVE", "EVENTS"}}
+	for _, wh := range hooks {
+		t.add(wh.ID, wh.URL, formatBool(wh.Active), strings.Join(wh.Events, ","))
+	}
+	return t
+}
+
+func deliveryTable(deliveries ...atoship.WebhookDelivery) *table {
+	t := &table{headers: []string{"ID", "EVENT", "TYPE", "ATTEMPT", "SUCCESS", "STATUS", "ERROR", "CREATED"}}
+	for _, d := range deliveries {
+		status := ""
+		if d.StatusCode != 0 {
+			status = strconv.Itoa(d.StatusCode)
+		}
+		t.add(d.ID, d.EventID, d.EventType, strconv.Itoa(d.Attempt), formatBool(d.Success), status, d.Error, formatTime(d.CreatedAt))
+	}
+	return t
+}
+
+func (c *cli) webhooksList(args []string) error {
+	fs, g := c.newFlagSet("atoship webhooks list", "")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 0 {
+		return usageError(fs, "unexpected arguments")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	hooks, err := client.Webhooks.List(c.ctx)
+	if err != nil {
+		return err
+	}
+	if hooks == nil {
+		hooks = []atoship.Webhook{}
+	}
+	return g.print(c.stdout, hooks, webhookTable(hooks...))
+}
+
+// webhookArg parses the flags of a command that takes one webhook ID and
+// returns the ID and a client
+func (c *cli) webhookArg(fs *flag.FlagSet, g *globalFlags, args []string) (string, *atoship.Client, error) {
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(pos) != 1 {
+		return "", nil, usageError(fs, "expected a webhook ID")
+	}
+	client, err := g.client()
+	if err != nil {
+		return "", nil, err
+	}
+	return pos[0], client, nil
+}
+
+func (c *cli) webhooksGet(args []string) error {
+	fs, g := c.newFlagSet("atoship webhooks get", "WEBHOOK_ID")
+	id, client, err := c.webhookArg(fs, g, args)
+	if err != nil {
+		return err
+	}
+	wh, err := client.Webhooks.Get(c.ctx, id)
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, wh, webhookTable(*wh))
+}
+
+func (c *cli) webhooksUpdate(args []string) error {
+	fs, g := c.newFlagSet("atoship webhooks update", "WEBHOOK_ID")
+	url := fs.String("url", "", "new endpoint URL")
+	events := fs.String("events", "", "comma-separated event types to subscribe to")
+	id, client, err := c.webhookArg(fs, g, args)
+	if err != nil {
+		return err
+	}
+	wh, err := client.Webhooks.Update(c.ctx, id, &atoship.UpdateWebhookRequest{
+		URL:    *url,
+		Events: splitList(*events),
+	})
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, wh, webhookTable(*wh))
+}
+
+func (c *cli) webhooksEnable(args []string) error {
+	return c.setWebhookActive("enable", args, true)
+}
+
+func (c *cli) webhooksDisable(args []string) error {
+	return c.setWebhookActive("disable", args, false)
+}
+
+func (c *cli) setWebhookActive(name string, args []string, active bool) error {
+	fs, g := c.newFlagSet("atoship webhooks "+name, "WEBHOOK_ID")
+	id, client, err := c.webhookArg(fs, g, args)
+	if err != nil {
+		return err
+	}
+	wh, err := client.Webhooks.SetActive(c.ctx, id, active)
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, wh, webhookTable(*wh))
+}
+
+func (c *cli) webhooksCreate(args []string) error {
+	fs, g := c.newFlagSet("atoship webhooks create", "URL")
+	events := fs.String("events", "", "comma-separated event types to subscribe to (default all)")
+	inactive := fs.Bool("inactive", false, "create the webhook paused")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 1 {
+		return usageError(fs, "expected the endpoint URL")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	req := &atoship.CreateWebhookRequest{
+		URL:    pos[0],
+		Events: splitList(*events),
+		Active: !*inactive,
+	}
+	if len(req.Events) == 0 {
+		req.Events = atoship.WebhookEventTypes()
+	}
+	wh, err := client.Webhooks.Create(c.ctx, req)
+	if err != nil {
+		return err
+	}
+	if err := g.print(c.stdout, wh, webhookTable(*wh)); err != nil {
+		return err
+	}
+	if g.output == "table" {
+		fmt.Fprintf(c.stderr, "Signing secret: %s\n", wh.Secret)
+	}
+	return nil
+}
+
+func (c *cli) webhooksDelete(args []string) error {
+	fs, g := c.newFlagSet("atoship webhooks delete", "WEBHOOK_ID...")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) == 0 {
+		return usageError(fs, "expected a webhook ID")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	for _, id := range pos {
+		if err := client.Webhooks.Delete(c.ctx, id); err != nil {
+			return err
+		}
+		fmt.Fprintf(c.stderr, "Deleted webhook %s\n", id)
+	}
+	return nil
+}
+
+func (c *cli) webhooksRotateSecret(args []string) error {
+	fs, g := c.newFlagSet("atoship webhooks rotate-secret", "WEBHOOK_ID")
+	id, client, err := c.webhookArg(fs, g, args)
+	if err != nil {
+		return err
+	}
+	secret, err := client.Webhooks.RotateSecret(c.ctx, id)
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, map[string]string{"id": id, "secret": secret}, keyValues("ID", id, "Secret", secret))
+}
+
+func (c *cli) webhooksTest(args []string) error {
+	fs, g := c.newFlagSet("atoship webhooks test", "WEBHOOK_ID")
+	eventType := fs.String("event", atoship.EventOrderCreated, "type of the sample event")
+	id, client, err := c.webhookArg(fs, g, args)
+	if err != nil {
+		return err
+	}
+	delivery, err := client.Webhooks.SendTest(c.ctx, id, *eventType)
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, delivery, deliveryTable(*delivery))
+}
+
+func (c *cli) webhooksDeliveries(args []string) error {
+	fs, g := c.newFlagSet("atoship webhooks deliveries", "WEBHOOK_ID")
+	limit := fs.Int("limit", 20, "number of recent deliveries to show; 0 shows all")
+	failed := fs.Bool("failed", false, "only show failed deliveries")
+	id, client, err := c.webhookArg(fs, g, args)
+	if err != nil {
+		return err
+	}
+	deliveries := []atoship.WebhookDelivery{}
+	pager := client.Webhooks.ListDeliveriesPager(id, nil)
+	for pager.More() && (*limit <= 0 || len(deliveries) < *limit) {
+		page, err := pager.Next(c.ctx)
+		if err != nil {
+			return err
+		}
+		for _, d := range page.Items {
+			if *failed && d.Success {
+				continue
+			}
+			if *limit > 0 && len(deliveries) == *limit {
+				break
+			}
+			deliveries = append(deliveries, d)
+		}
+	}
+	return g.print(c.stdout, deliveries, deliveryTable(deliveries...))
+}
+
+func (c *cli) webhooksRetry(args []string) error {
+	fs, g := c.newFlagSet("atoship webhooks retry", "WEBHOOK_ID DELIVERY_ID")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 2 {
+		return usageError(fs, "expected a webhook ID and a delivery ID")
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	delivery, err := client.Webhooks.RetryDelivery(c.ctx, pos[0], pos[1])
+	if err != nil {
+		return err
+	}
+	return g.print(c.stdout, delivery, deliveryTable(*delivery))
+}
+
+// webhooksListen forwards events to a local handler, either by registering
+// a temporary webhook that points at a tunnel to this machine, or by
+// replaying events read from a file
+func (c *cli) webhooksListen(args []string) error {
+	fs, g := c.newFlagSet("atoship webhooks listen", "")
+	fs.Usage = func() {
+		fmt.Fprint(c.stderr, `Usage: atoship webhooks listen --forward-to URL (--public-url URL | --replay FILE) [flags]
+
+Registers a temporary webhook pointing at --public-url, a tunnel (such as
+ngrok or cloudflared) to --listen on this machine, and forwards each verified
+delivery to --forward-to. The webhook is deleted on exit.
+
+With --replay, events are read from FILE (or stdin for "-") instead, as JSON
+objects or arrays of objects, and forwarded once each.
+
+Forwarded events are re-signed with --secret, so the local handler verifies
+them with the same secret in every mode.
+
+Flags:
+`)
+		fs.PrintDefaults()
+	}
+
+	forwardTo := fs.String("forward-to", "", "local URL to POST events to (required)")
+	publicURL := fs.String("public-url", "", "publicly reachable URL that tunnels to --listen")
+	listenAddr := fs.String("listen", "localhost:4242", "address to receive deliveries on")
+	replay := fs.String("replay", "", "file of events to replay, or - for stdin")
+	events := fs.String("events", "", "comma-separated event types to subscribe to (default all)")
+	secret := fs.String("secret", "", "secret to sign forwarded events with (default $ATOSHIP_WEBHOOK_SECRET or "+defaultLocalSecret+")")
+	pos, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) != 0 || *forwardTo == "" || (*publicURL == "") == (*replay == "") {
+		fs.Usage()
+		return errUsage
+	}
+	if *secret == "" {
+		*secret = firstNonEmpty(os.Getenv("ATOSHIP_WEBHOOK_SECRET"), defaultLocalSecret)
+	}
+
+	fwd := &forwarder{
+		target: *forwardTo,
+		secret: *secret,
+		client: &http.Client{Timeout: 30 * time.Second},
+		out:    c.stdout,
+	}
+
+	if *replay != "" {
+		return c.replayEvents(fwd, *replay)
+	}
+
+	client, err := g.client()
+	if err != nil {
+		return err
+	}
+	return listenAndForward(c.ctx, client, fwd, *publicURL, *listenAddr, splitList(*events))
+}
+
+// forwarder re-signs events and POSTs them to a local handler
+type forwarder struct {
+	target string
+	secret string
+	client *http.Client
+	out    io.Writer
+}
+
+// forward POSTs payload to the target and returns the response status
+func (f *forwarder) forward(ctx context.Context, payload []byte) (int, error) {
+	var event webhooks.Event
+	json.Unmarshal(payload, &event)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.target, bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhooks.SignatureHeader, webhooks.Sign(payload, f.secret, time.Now()))
+
+	resp, err := f.client.Do(req)
+	if err != nil {
+		fmt.Fprintf(f.out, "%s  --> %-24s %s  [error: %v]\n", time.Now().Format(time.TimeOnly), event.Type, event.ID, err)
+		return 0, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	fmt.Fprintf(f.out, "%s  --> %-24s %s  [%d]\n", time.Now().Format(time.TimeOnly), event.Type, event.ID, resp.StatusCode)
+	return resp.StatusCode, nil
+}
+
+// replayEvents forwards every event in the named file, or stdin for "-"
+func (c *cli) replayEvents(fwd *forwarder, name string) error {
+	raw, err := c.readInput(name)
+	if err != nil {
+		return err
+	}
+	payloads, err := readEvents(bytes.NewReader(raw))
+	if err != nil {
+		return fmt.Errorf("reading events: %w", err)
+	}
+
+	failed := 0
+	for _, payload := range payloads {
+		if err := c.ctx.Err(); err != nil {
+			return err
+		}
+		status, err := fwd.forward(c.ctx, payload)
+		if err != nil || status < 200 || status >= 300 {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d events were not accepted", failed, len(payloads))
+	}
+	return nil
+}
+
+// readEvents decodes a stream of JSON events, each either an object or an
+// array of objects
+func readEvents(r io.Reader) ([][]byte, error) {
+	var payloads [][]byte
+	dec := json.NewDecoder(r)
+	for {
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err == io.EOF {
+			return payloads, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && trimmed[0] == '[' {
+			var list []json.RawMessage
+			if err := json.Unmarshal(raw, &list); err != nil {
+				return nil, err
+			}
+			for _, item := range list {
+				payloads = append(payloads, compact(item))
+			}
+			continue
+		}
+		payloads = append(payloads, compact(raw))
+	}
+}
+
+// compact strips insignificant whitespace from a JSON value
+func compact(raw []byte) []byte {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, raw); err != nil {
+		return raw
+	}
+	return buf.Bytes()
+}
+
+// listenAndForward registers a temporary webhook for publicURL, forwards
+// the deliveries received on addr until ctx is done, then deletes the
+// webhook
+func listenAndForward(ctx context.Context, client *atoship.Client, fwd *forwarder, publicURL, addr string, events []string) error {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	if len(events) == 0 {
+		events = atoship.WebhookEventTypes()
+	}
+	wh, err := client.Webhooks.Create(ctx, &atoship.CreateWebhookRequest{
+		URL:    publicURL,
+		Events: events,
+		Active: true,
+	})
+	if err != nil {
+		ln.Close()
+		return fmt.Errorf("registering webhook: %w", err)
+	}
+	defer func() {
+		cleanup, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := client.Webhooks.Delete(cleanup, wh.ID); err != nil {
+			fmt.Fprintf(fwd.out, "Failed to delete webhook %s: %v\n", wh.ID, err)
+			return
+		}
+		fmt.Fprintf(fwd.out, "Deleted webhook %s\n", wh.ID)
+	}()
+
+	fmt.Fprintf(fwd.out, "Registered webhook %s for %s\n", wh.ID, publicURL)
+	fmt.Fprintf(fwd.out, "Listening on %s, forwarding to %s\n", ln.Addr(), fwd.target)
+	fmt.Fprintf(fwd.out, "Forwarded events are signed with %q. Press Ctrl+C to stop.\n", fwd.secret)
+
+	srv := &http.Server{
+		Handler:           relayHandler(fwd, wh.Secret),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errc := make(chan error, 1)
+	go func() {
+		errc <- srv.Serve(ln)
+	}()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(shutdown); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// relayHandler verifies deliveries signed with secret and forwards them,
+// responding with the local handler's status so atoship retries deliveries
+// the handler rejected
+func relayHandler(fwd *forwarder, secret string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		payload, err := io.ReadAll(io.LimitReader(r.Body, webhooks.DefaultMaxBodyBytes))
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+		err = webhooks.Verify(payload, r.Header.Get(webhooks.SignatureHeader), []string{secret}, webhooks.DefaultTolerance, time.Now())
+		if err != nil {
+			fmt.Fprintf(fwd.out, "Rejected delivery: %v\n", err)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+
+		status, err := fwd.forward(r.Context(), payload)
+		if err != nil {
+			status = http.StatusBadGateway
+		}
+		w.WriteHeader(status)
+	})
+}
+
+// splitList splits a comma-separated flag value, dropping empty items
+func splitList(s string) []string {
+	var out []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
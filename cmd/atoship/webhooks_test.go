@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/atoship-LLC/atoship-go/atoship/webhooks"
+)
+
+// localHandler is a stand-in for the handler events are forwarded to
+type localHandler struct {
+	mu       sync.Mutex
+	status   int
+	payloads []string
+	errs     []error
+}
+
+func (h *localHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	payload, _ := io.ReadAll(r.Body)
+	err := webhooks.Verify(payload, r.Header.Get(webhooks.SignatureHeader), []string{"whsec_forward"}, webhooks.DefaultTolerance, time.Now())
+	h.mu.Lock()
+	h.payloads = append(h.payloads, string(payload))
+	h.errs = append(h.errs, err)
+	status := h.status
+	h.mu.Unlock()
+	if status == 0 {
+		status = http.StatusNoContent
+	}
+	w.WriteHeader(status)
+}
+
+func TestListenReplay(t *testing.T) {
+	isolate(t)
+	local := &localHandler{}
+	srv := httptest.NewServer(local)
+	defer srv.Close()
+
+	events := `{"id":"evt_1","type":"order.created","data":{"id":"ord_1"}}
+[
+  {"id": "evt_2", "type": "label.purchased"},
+  {"id": "evt_3", "type": "tracking.delivered"}
+]`
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), []string{"webhooks", "listen", "--replay", "-", "--forward-to", srv.URL, "--secret", "whsec_forward"},
+		strings.NewReader(events), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("exit code = %d; stderr:\n%s", code, stderr.String())
+	}
+
+	want := []string{
+		`{"id":"evt_1","type":"order.created","data":{"id":"ord_1"}}`,
+		`{"id":"evt_2","type":"label.purchased"}`,
+		`{"id":"evt_3","type":"tracking.delivered"}`,
+	}
+	if len(local.payloads) != len(want) {
+		t.Fatalf("forwarded %d events, want %d", len(local.payloads), len(want))
+	}
+	for i := range want {
+		if local.payloads[i] != want[i] {
+			t.Errorf("event %d = %s, want %s", i, local.payloads[i], want[i])
+		}
+		if local.errs[i] != nil {
+			t.Errorf("event %d was not signed with --secret: %v", i, local.errs[i])
+		}
+	}
+	if !strings.Contains(stdout.String(), "order.created") || !strings.Contains(stdout.String(), "[204]") {
+		t.Errorf("stdout does not log the forwarded events:\n%s", stdout.String())
+	}
+
+	// Events the handler rejects fail the command
+	local.mu.Lock()
+	local.status = http.StatusInternalServerError
+	local.mu.Unlock()
+	stderr.Reset()
+	code = run(context.Background(), []string{"webhooks", "listen", "--replay", "-", "--forward-to", srv.URL, "--secret", "whsec_forward"},
+		strings.NewReader(events), io.Discard, &stderr)
+	if code != exitError || !strings.Contains(stderr.String(), "3 of 3 events were not accepted") {
+		t.Errorf("exit code = %d, stderr = %q; want every event reported as rejected", code, stderr.String())
+	}
+}
+
+func TestRelayHandler(t *testing.T) {
+	const secret = "whsec_remote"
+	const payload = `{"id":"evt_1","type":"order.created"}`
+
+	tests := []struct {
+		name      string
+		method    string
+		signature string
+		local     int
+		want      int
+		forwarded bool
+	}{
+		{"verified", http.MethodPost, webhooks.Sign([]byte(payload), secret, time.Now()), 0, http.StatusNoContent, true},
+		{"rejected by the local handler", http.MethodPost, webhooks.Sign([]byte(payload), secret, time.Now()), http.StatusInternalServerError, http.StatusInternalServerError, true},
+		{"wrong secret", http.MethodPost, webhooks.Sign([]byte(payload), "whsec_other", time.Now()), 0, http.StatusUnauthorized, false},
+		{"stale signature", http.MethodPost, webhooks.Sign([]byte(payload), secret, time.Now().Add(-time.Hour)), 0, http.StatusUnauthorized, false},
+		{"malformed signature", http.MethodPost, "v1=deadbeef", 0, http.StatusUnauthorized, false},
+		{"missing signature", http.MethodPost, "", 0, http.StatusUnauthorized, false},
+		{"wrong method", http.MethodGet, "", 0, http.StatusMethodNotAllowed, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local := &localHandler{status: tt.local}
+			srv := httptest.NewServer(local)
+			defer srv.Close()
+			var out bytes.Buffer
+			fwd := &forwarder{target: srv.URL, secret: "whsec_forward", client: srv.Client(), out: &out}
+
+			req := httptest.NewRequest(tt.method, "/", strings.NewReader(payload))
+			if tt.signature != "" {
+				req.Header.Set(webhooks.SignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			relayHandler(fwd, secret).ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if forwarded := len(local.payloads) > 0; forwarded != tt.forwarded {
+				t.Fatalf("forwarded = %v, want %v", forwarded, tt.forwarded)
+			}
+			if tt.forwarded && (local.payloads[0] != payload || local.errs[0] != nil) {
+				t.Errorf("forwarded %s, verification error %v; want the payload re-signed", local.payloads[0], local.errs[0])
+			}
+			if rec.Code == http.StatusUnauthorized && !strings.Contains(out.String(), "Rejected delivery") {
+				t.Errorf("output = %q, want the rejection logged", out.String())
+			}
+		})
+	}
+}
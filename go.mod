@@ -1,6 +1,6 @@
 module github.com/atoship-LLC/atoship-go
 
-go 1.20
+go 1.21
 
 require (
 	github.com/go-resty/resty/v2 v2.11.0